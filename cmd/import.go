@@ -41,7 +41,11 @@ var importCmd = &cobra.Command{
 	Use:   "import [file]",
 	Short: "Import galaxy dump (https://downloads.spansh.co.uk/galaxy.json.gz)",
 	Long: `Imports galaxy dump into local db, stripped down of unimportant information
-for routing.`,
+for routing.
+
+The index database must be rebuilt when its format changes, eg. white
+dwarf stars added in index format version 3. Delete the old index and
+galaxy databases and run the import again.`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: importer.Import,
 }
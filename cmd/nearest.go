@@ -0,0 +1,77 @@
+/*
+Copyright © 2020 Lukáš Němec <lu.nemec@gmail.com>
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+1. Redistributions of source code must retain the above copyright notice,
+   this list of conditions and the following disclaimer.
+
+2. Redistributions in binary form must reproduce the above copyright notice,
+   this list of conditions and the following disclaimer in the documentation
+   and/or other materials provided with the distribution.
+
+3. Neither the name of the copyright holder nor the names of its contributors
+   may be used to endorse or promote products derived from this software
+   without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+package cmd
+
+import (
+	"github.com/lunemec/ed-router/pkg/route"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// nearestCmd represents the nearest command
+var nearestCmd = &cobra.Command{
+	Use:   "nearest [system]",
+	Short: "List systems nearest to the system",
+	Long: `Lists systems nearest to the system, or all the systems within
+--radius LY of it. The system may be given as "xyz:x,y,z" coordinates.`,
+	Args: cobra.ExactArgs(1),
+	RunE: route.Nearest,
+}
+
+// nearestScoopableCmd represents the nearest-scoopable command
+var nearestScoopableCmd = &cobra.Command{
+	Use:   "nearest-scoopable [system]",
+	Short: "List systems with scoopable star nearest to the system",
+	Args:  cobra.ExactArgs(1),
+	RunE:  route.NearestScoopable,
+}
+
+// nearestNeutronCmd represents the nearest-neutron command
+var nearestNeutronCmd = &cobra.Command{
+	Use:   "nearest-neutron [system]",
+	Short: "List systems with neutron star nearest to the system",
+	Args:  cobra.ExactArgs(1),
+	RunE:  route.NearestNeutron,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{nearestCmd, nearestScoopableCmd, nearestNeutronCmd} {
+		rootCmd.AddCommand(cmd)
+		nearestFlags(cmd.Flags())
+	}
+}
+
+// nearestFlags adds flags of the nearest commands.
+func nearestFlags(flags *pflag.FlagSet) {
+	flags.Int("count", 5, "how many nearest systems to list")
+	flags.Float64("radius", 0, "list all the systems within radius in LY instead")
+}
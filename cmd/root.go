@@ -31,20 +31,31 @@ POSSIBILITY OF SUCH DAMAGE.
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
 
+	"github.com/lunemec/ed-router/pkg/config"
+	"github.com/lunemec/ed-router/pkg/pather"
 	"github.com/lunemec/ed-router/pkg/route"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "ed-router [from] [to]",
+	Use:   "ed-router [from] [via...] [to]",
 	Short: "Elite Dangerous routing tool",
 	Long: `A tool that uses A* pathing algorithm to find shortest
-possible path.`,
+possible path. Systems between [from] and [to] are visited in order.
+
+When more systems share the name, pick one by its ID64 or coordinates:
+"Name@id64" or "Name@x,y,z". Systems may also be given only by ID64 as
+"id64:10477373803", or by coordinates in LY as "xyz:-9530.5,-910.3,19808.1"
+for the nearest known system to them.`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: route.Route,
 }
@@ -52,14 +63,62 @@ possible path.`,
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 }
 
+// interruptContext returns context cancelled on first Ctrl-C, so the
+// search stops cleanly. Second Ctrl-C kills the program.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			fmt.Println("\nInterrupted, stopping the search...")
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(interrupt)
+	}()
+	return ctx, cancel
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
+
+	rootCmd.PersistentFlags().String("config", "", "config file (default is $HOME/.config/ed-router/config.yaml)")
+	rootCmd.PersistentFlags().String("data-dir", "", fmt.Sprintf("directory with the databases, relative DB paths are relative to it [$%s]", config.EnvDataDir))
+	rootCmd.PersistentFlags().String("index-db", "", fmt.Sprintf("index database file (default %q) [$%s]", config.DefaultIndexDB, config.EnvIndexDB))
+	rootCmd.PersistentFlags().String("galaxy-db", "", fmt.Sprintf("galaxy database file (default %q) [$%s]", config.DefaultGalaxyDB, config.EnvGalaxyDB))
+
+	rootCmd.Flags().StringSlice("via", nil, "systems to visit in order on the way to [to], after any given as arguments")
+	routeFlags(rootCmd.Flags())
+}
+
+// routeFlags adds flags for the ship and route planning options.
+func routeFlags(flags *pflag.FlagSet) {
+	flags.StringP("optimize", "o", "time", fmt.Sprintf("what to optimize the route for: %s", strings.Join(pather.CostFuncNames(), ", ")))
+	flags.String("ship", "", "ship loadout JSON file (journal Loadout event, EDSY or Coriolis export)")
+	flags.StringP("profile", "p", "", "name of ship profile from the config file")
+	flags.Float64("cargo", 0, "cargo aboard in T")
+	flags.Float64("fuel", 0, "fuel in the tank at the start in T (default full tank)")
+	flags.Bool("white-dwarf", false, "allow supercharging from white dwarfs (1.5x range, damages the ship)")
+	flags.StringToInt("injections", nil, "FSD injections available for the route by grade, eg. basic=3,standard=1,premium=1")
+	flags.Float64("reserve", 1, "minimum fuel in T to keep when arriving to system without scoopable star")
+	flags.StringSlice("avoid", nil, "systems (name or ID64) or sectors (sector:<name>) the route must not go through")
+	flags.String("keep-out", "", "YAML file with spheres, boxes or cylinders the route must keep out of")
+	flags.StringSlice("permit", nil, "permit-locked systems or sectors you have permit for, \"all\" to ignore permits")
+	flags.Float64("epsilon", 1, "weight of the estimate in weighted A*, eg. 1.05 finds route at most 5% worse faster")
+	flags.Duration("anytime", 0, "keep improving the route found with --epsilon above 1 for this long after it is found, eg. 2s")
+	flags.Int("budget", 0, "maximum number of search nodes to expand, 0 is unlimited")
+	flags.Float64Slice("corridors", pather.DefaultCorridors, "widths of corridors around the straight line to search in, in multiples of jump range, wider are tried when no path is found")
 }
 
 // initConfig reads in config file and ENV variables if set.
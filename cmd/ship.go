@@ -0,0 +1,91 @@
+/*
+Copyright © 2020 Lukáš Němec <lu.nemec@gmail.com>
+All rights reserved.
+
+Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+
+1. Redistributions of source code must retain the above copyright notice,
+   this list of conditions and the following disclaimer.
+
+2. Redistributions in binary form must reproduce the above copyright notice,
+   this list of conditions and the following disclaimer in the documentation
+   and/or other materials provided with the distribution.
+
+3. Neither the name of the copyright holder nor the names of its contributors
+   may be used to endorse or promote products derived from this software
+   without specific prior written permission.
+
+THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+POSSIBILITY OF SUCH DAMAGE.
+*/
+package cmd
+
+import (
+	"github.com/lunemec/ed-router/pkg/profiles"
+
+	"github.com/spf13/cobra"
+)
+
+// shipCmd represents the ship command
+var shipCmd = &cobra.Command{
+	Use:   "ship",
+	Short: "Manage named ship profiles",
+	Long: `Manage named ship profiles stored in the config file, use them
+for routing with --profile [name].`,
+}
+
+var shipAddCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Add ship profile from loadout file and/or flags",
+	Args:  cobra.ExactArgs(1),
+	RunE:  profiles.Add,
+}
+
+var shipListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List ship profiles",
+	Args:  cobra.NoArgs,
+	RunE:  profiles.List,
+}
+
+var shipRemoveCmd = &cobra.Command{
+	Use:   "remove [name]",
+	Short: "Remove ship profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  profiles.Remove,
+}
+
+var shipShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show ship profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  profiles.Show,
+}
+
+func init() {
+	rootCmd.AddCommand(shipCmd)
+	shipCmd.AddCommand(shipAddCmd, shipListCmd, shipRemoveCmd, shipShowCmd)
+
+	flags := shipAddCmd.Flags()
+	flags.String("loadout", "", "ship loadout JSON file (journal Loadout event, EDSY or Coriolis export)")
+	flags.Bool("force", false, "overwrite existing profile")
+	flags.Float64("fuel-tank", 0, "main fuel tank size in T")
+	flags.Float64("mass", 0, "ship mass with full fuel tank and no cargo in T")
+	flags.Int("fsd-class", 0, "FSD class (2-7), sets stock FSD stats")
+	flags.String("fsd-rating", "", "FSD rating (A-E), sets stock FSD stats")
+	flags.Float64("fsd-optimal-mass", 0, "FSD optimal mass in T, if engineered")
+	flags.Float64("max-fuel-per-jump", 0, "FSD max fuel per jump in T, if engineered")
+	flags.Float64("guardian-booster", 0, "guardian FSD booster range bonus in LY")
+	flags.Float64("scoop-rate", 0, "fuel scoop rate in kg/s")
+	flags.Float64("reservoir", 0, "FSD-side fuel reservoir size in T")
+}
@@ -3,7 +3,6 @@ module github.com/lunemec/ed-router
 go 1.15
 
 require (
-	github.com/beefsack/go-astar v0.0.0-20200827232313-4ecf9e304482
 	github.com/dhconnelly/rtreego v1.0.0
 	github.com/json-iterator/go v1.1.10
 	github.com/kr/text v0.2.0 // indirect
@@ -13,7 +12,7 @@ require (
 	github.com/pkg/errors v0.9.1
 	github.com/pkg/profile v1.5.0
 	github.com/spf13/cobra v1.0.0
-	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.6.1
 	github.com/vbauerster/mpb/v5 v5.3.0
 	go.etcd.io/bbolt v1.3.5
@@ -21,5 +20,5 @@ require (
 	golang.org/x/sys v0.0.0-20200930185726-fdedc70b468f // indirect
 	gonum.org/v1/gonum v0.8.1
 	gopkg.in/check.v1 v1.0.0-20200227125254-8fa46927fb4f // indirect
-	gopkg.in/yaml.v3 v3.0.0-20200615113413-eeeca48fe776 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200615113413-eeeca48fe776
 )
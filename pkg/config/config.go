@@ -0,0 +1,158 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/lunemec/ed-router/pkg/ship"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultIndexDB  = "index_xyz.db"
+	DefaultGalaxyDB = "galaxy.db"
+
+	// Environment variables overriding the config file.
+	EnvDataDir  = "ED_ROUTER_DATA_DIR"
+	EnvIndexDB  = "ED_ROUTER_INDEX_DB"
+	EnvGalaxyDB = "ED_ROUTER_GALAXY_DB"
+)
+
+// Config is the contents of the config file.
+type Config struct {
+	// DataDir is directory relative DB paths are relative to.
+	DataDir  string                  `yaml:"data_dir,omitempty"`
+	IndexDB  string                  `yaml:"index_db,omitempty"`
+	GalaxyDB string                  `yaml:"galaxy_db,omitempty"`
+	Ships    map[string]ship.Loadout `yaml:"ships,omitempty"`
+	// Avoid are systems or sectors (sector:<name>) routes never go through.
+	Avoid []string `yaml:"avoid,omitempty"`
+	// Permits are permit-locked systems or sectors the pilot can enter.
+	Permits []string `yaml:"permits,omitempty"`
+	// KeepOut is file with volumes routes keep out of.
+	KeepOut string `yaml:"keep_out,omitempty"`
+}
+
+// DefaultPath returns default config file location,
+// ~/.config/ed-router/config.yaml on Linux.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "ed-router.yaml"
+	}
+	return filepath.Join(dir, "ed-router", "config.yaml")
+}
+
+// Path returns config file location given by the --config flag,
+// or the default one.
+func Path(cmd *cobra.Command) string {
+	path, err := cmd.Flags().GetString("config")
+	if err != nil || path == "" {
+		return DefaultPath()
+	}
+	return path
+}
+
+// FromCommand loads config file given by the --config flag and overrides
+// it with environment variables and then with --data-dir, --index-db and
+// --galaxy-db flags.
+func FromCommand(cmd *cobra.Command) (*Config, error) {
+	c, err := Load(Path(cmd))
+	if err != nil {
+		return nil, err
+	}
+
+	for env, value := range map[string]*string{
+		EnvDataDir:  &c.DataDir,
+		EnvIndexDB:  &c.IndexDB,
+		EnvGalaxyDB: &c.GalaxyDB,
+	} {
+		if v, ok := os.LookupEnv(env); ok {
+			*value = v
+		}
+	}
+
+	flags := cmd.Flags()
+	for flag, value := range map[string]*string{
+		"data-dir":  &c.DataDir,
+		"index-db":  &c.IndexDB,
+		"galaxy-db": &c.GalaxyDB,
+	} {
+		if !flags.Changed(flag) {
+			continue
+		}
+		*value, err = flags.GetString(flag)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Load reads config file, missing file is the same as empty one.
+func Load(path string) (*Config, error) {
+	var c Config
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &c, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read config: %s", path)
+	}
+	err = yaml.Unmarshal(b, &c)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to decode config: %s", path)
+	}
+	return &c, nil
+}
+
+// Save writes config file, creating its directory if needed.
+func (c *Config) Save(path string) error {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return errors.Wrap(err, "unable to encode config")
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create config directory for: %s", path)
+	}
+	err = ioutil.WriteFile(path, b, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "unable to write config: %s", path)
+	}
+	return nil
+}
+
+// IndexDBPath returns path to the index DB.
+func (c *Config) IndexDBPath() string {
+	return c.dbPath(c.IndexDB, DefaultIndexDB)
+}
+
+// GalaxyDBPath returns path to the galaxy DB.
+func (c *Config) GalaxyDBPath() string {
+	return c.dbPath(c.GalaxyDB, DefaultGalaxyDB)
+}
+
+func (c *Config) dbPath(path, defaultPath string) string {
+	if path == "" {
+		path = defaultPath
+	}
+	if c.DataDir == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(c.DataDir, path)
+}
+
+// Ship returns ship profile by name.
+func (c *Config) Ship(name string) (ship.Loadout, error) {
+	l, ok := c.Ships[name]
+	if !ok {
+		return l, errors.Errorf("unknown ship profile: %s", name)
+	}
+	return l, nil
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lunemec/ed-router/pkg/ship"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadMissing(t *testing.T) {
+	c, err := Load("does-not-exist.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, &Config{}, c)
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ed-router")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "nested", "config.yaml")
+	c := &Config{
+		IndexDB:  "/data/index_xyz.db",
+		GalaxyDB: "/data/galaxy.db",
+		Ships: map[string]ship.Loadout{
+			"conda": {
+				FuelTank:                32,
+				Mass:                    346.9,
+				FSDClass:                5,
+				FSDRating:               "A",
+				FSDOptimalMass:          1692.6,
+				MaxFuelPerJump:          5,
+				GuardianFSDBoosterRange: 10.5,
+				ScoopRate:               878,
+			},
+		},
+	}
+	assert.NoError(t, c.Save(path))
+
+	loaded, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, c, loaded)
+
+	_, err = loaded.Ship("conda")
+	assert.NoError(t, err)
+	_, err = loaded.Ship("sidewinder")
+	assert.Error(t, err)
+}
+
+func testCommand(t *testing.T, configPath string, args ...string) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("config", configPath, "")
+	cmd.Flags().String("data-dir", "", "")
+	cmd.Flags().String("index-db", "", "")
+	cmd.Flags().String("galaxy-db", "", "")
+	assert.NoError(t, cmd.Flags().Parse(args))
+	return cmd
+}
+
+func TestDBPathsDefault(t *testing.T) {
+	c, err := FromCommand(testCommand(t, "does-not-exist.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultIndexDB, c.IndexDBPath())
+	assert.Equal(t, DefaultGalaxyDB, c.GalaxyDBPath())
+}
+
+func TestDBPathsPrecedence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ed-router")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yaml")
+	assert.NoError(t, (&Config{DataDir: "/config", IndexDB: "config_index.db", GalaxyDB: "/abs/galaxy.db"}).Save(path))
+
+	c, err := FromCommand(testCommand(t, path))
+	assert.NoError(t, err)
+	assert.Equal(t, "/config/config_index.db", c.IndexDBPath())
+	assert.Equal(t, "/abs/galaxy.db", c.GalaxyDBPath())
+
+	os.Setenv(EnvDataDir, "/env")
+	defer os.Unsetenv(EnvDataDir)
+	c, err = FromCommand(testCommand(t, path))
+	assert.NoError(t, err)
+	assert.Equal(t, "/env/config_index.db", c.IndexDBPath())
+
+	c, err = FromCommand(testCommand(t, path, "--data-dir", "/flag", "--galaxy-db", "galaxy.db"))
+	assert.NoError(t, err)
+	assert.Equal(t, "/flag/config_index.db", c.IndexDBPath())
+	assert.Equal(t, "/flag/galaxy.db", c.GalaxyDBPath())
+}
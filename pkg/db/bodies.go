@@ -0,0 +1,111 @@
+package db
+
+import (
+	"strings"
+
+	"github.com/lunemec/ed-router/pkg/models/dump"
+)
+
+func NeutronInRange(bodies []dump.Body) bool {
+	_, ok := closestStar(bodies, isNeutron)
+	return ok
+}
+
+// NeutronDistance returns distance from arrival in Ls of the closest
+// neutron star in range, 0 if there is none.
+func NeutronDistance(bodies []dump.Body) float64 {
+	distance, _ := closestStar(bodies, isNeutron)
+	return distance
+}
+
+// WhiteDwarfInRange is true when there is white dwarf star in range.
+// White dwarfs are kept separate from neutrons, since supercharging from
+// them gives smaller boost and is more dangerous.
+func WhiteDwarfInRange(bodies []dump.Body) bool {
+	_, ok := closestStar(bodies, isWhiteDwarf)
+	return ok
+}
+
+// WhiteDwarfDistance returns distance from arrival in Ls of the closest
+// white dwarf in range, 0 if there is none.
+func WhiteDwarfDistance(bodies []dump.Body) float64 {
+	distance, _ := closestStar(bodies, isWhiteDwarf)
+	return distance
+}
+
+func isNeutron(subType string) bool {
+	return subType == "Neutron Star"
+}
+
+// isWhiteDwarf matches all the white dwarf classes, eg.
+// "White Dwarf (DA) Star".
+func isWhiteDwarf(subType string) bool {
+	return strings.HasPrefix(subType, "White Dwarf")
+}
+
+// closestStar returns distance from arrival of the closest star
+// with subtype matching the match func.
+func closestStar(bodies []dump.Body, match func(subType string) bool) (float64, bool) {
+	// Anything within 1000ls is considered OK.
+	var (
+		maxDistance float64 = 1000
+		closest             = maxDistance
+		found       bool
+	)
+	if bodies == nil || len(bodies) == 0 {
+		return 0, false
+	}
+
+	for _, body := range bodies {
+		if body.Type != "Star" {
+			continue
+		}
+		if match(body.SubType) {
+			if body.DistanceToArrival <= closest {
+				closest = body.DistanceToArrival
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return 0, false
+	}
+	return closest, true
+}
+
+func ScoopableInRange(bodies []dump.Body) bool {
+	// Anything within 1000ls is considered OK.
+	var maxDistance float64 = 1000
+	if bodies == nil || len(bodies) == 0 {
+		return false
+	}
+
+	for _, body := range bodies {
+		if body.Type != "Star" {
+			continue
+		}
+		switch body.SubType {
+		case
+			"A (Blue-White super giant) Star",
+			"A (Blue-White) Star",
+			"B (Blue-White super giant) Star",
+			"B (Blue-White) Star",
+			"F (White super giant) Star",
+			"F (White) Star",
+			"G (White-Yellow super giant) Star",
+			"G (White-Yellow) Star",
+			"K (Yellow-Orange giant) Star",
+			"K (Yellow-Orange) Star",
+			"M (Red dwarf) Star",
+			"M (Red giant) Star",
+			"M (Red super giant) Star",
+			"O (Blue-White) Star":
+			if body.DistanceToArrival <= maxDistance {
+				return true
+			}
+		}
+	}
+
+	return false
+}
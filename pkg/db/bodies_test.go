@@ -0,0 +1,44 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/lunemec/ed-router/pkg/models/dump"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeutronInRange(t *testing.T) {
+	assert.True(t, NeutronInRange([]dump.Body{{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 0}}))
+	assert.False(t, NeutronInRange([]dump.Body{{Type: "Star", SubType: "???", DistanceToArrival: 0}}))
+	assert.False(t, NeutronInRange([]dump.Body{{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 1000.1}}))
+}
+
+func TestNeutronDistance(t *testing.T) {
+	assert.EqualValues(t, 0, NeutronDistance([]dump.Body{{Type: "Star", SubType: "???", DistanceToArrival: 10}}))
+	assert.EqualValues(t, 0, NeutronDistance([]dump.Body{{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 1000.1}}))
+	assert.EqualValues(t, 12.5, NeutronDistance([]dump.Body{
+		{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 400},
+		{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 12.5},
+	}))
+}
+
+func TestWhiteDwarfInRange(t *testing.T) {
+	assert.True(t, WhiteDwarfInRange([]dump.Body{{Type: "Star", SubType: "White Dwarf (DA) Star", DistanceToArrival: 0}}))
+	assert.False(t, WhiteDwarfInRange([]dump.Body{{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 0}}))
+	assert.False(t, WhiteDwarfInRange([]dump.Body{{Type: "Star", SubType: "White Dwarf (D) Star", DistanceToArrival: 1000.1}}))
+	assert.False(t, NeutronInRange([]dump.Body{{Type: "Star", SubType: "White Dwarf (D) Star", DistanceToArrival: 0}}))
+}
+
+func TestWhiteDwarfDistance(t *testing.T) {
+	assert.EqualValues(t, 0, WhiteDwarfDistance([]dump.Body{{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 10}}))
+	assert.EqualValues(t, 25, WhiteDwarfDistance([]dump.Body{
+		{Type: "Star", SubType: "White Dwarf (DAB) Star", DistanceToArrival: 300},
+		{Type: "Star", SubType: "White Dwarf (DC) Star", DistanceToArrival: 25},
+	}))
+}
+
+func TestScoopableInRange(t *testing.T) {
+	assert.True(t, ScoopableInRange([]dump.Body{{Type: "Star", SubType: "K (Yellow-Orange giant) Star", DistanceToArrival: 0}}))
+	assert.False(t, ScoopableInRange([]dump.Body{{Type: "Star", SubType: "K (Yellow-Orange giant) Star", DistanceToArrival: 1000.1}}))
+	assert.False(t, ScoopableInRange([]dump.Body{{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 0}}))
+}
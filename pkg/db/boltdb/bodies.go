@@ -0,0 +1,36 @@
+package boltdb
+
+import (
+	store "github.com/lunemec/ed-router/pkg/db"
+	"github.com/lunemec/ed-router/pkg/models/dump"
+)
+
+// NeutronInRange is true when there is neutron star in range, see
+// store.NeutronInRange.
+func NeutronInRange(bodies []dump.Body) bool {
+	return store.NeutronInRange(bodies)
+}
+
+// NeutronDistance returns distance from arrival in Ls of the closest
+// neutron star in range, see store.NeutronDistance.
+func NeutronDistance(bodies []dump.Body) float64 {
+	return store.NeutronDistance(bodies)
+}
+
+// WhiteDwarfInRange is true when there is white dwarf star in range, see
+// store.WhiteDwarfInRange.
+func WhiteDwarfInRange(bodies []dump.Body) bool {
+	return store.WhiteDwarfInRange(bodies)
+}
+
+// WhiteDwarfDistance returns distance from arrival in Ls of the closest
+// white dwarf in range, see store.WhiteDwarfDistance.
+func WhiteDwarfDistance(bodies []dump.Body) float64 {
+	return store.WhiteDwarfDistance(bodies)
+}
+
+// ScoopableInRange is true when there is scoopable star in range, see
+// store.ScoopableInRange.
+func ScoopableInRange(bodies []dump.Body) bool {
+	return store.ScoopableInRange(bodies)
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 
+	store "github.com/lunemec/ed-router/pkg/db"
 	"github.com/lunemec/ed-router/pkg/models/dump"
 
 	"github.com/pkg/errors"
@@ -20,6 +21,8 @@ var (
 	bucketNames   = []byte("names")
 )
 
+var _ store.SystemStore = (*DB)(nil)
+
 type DB struct {
 	index  *bolt.DB
 	galaxy *bolt.DB
@@ -55,12 +58,21 @@ func Open(indexFile, galaxyFile string, readOnly bool) (*DB, error) {
 	if !readOnly {
 		err = prepareIndexDB(index)
 		if err != nil {
+			index.Close()
+			galaxy.Close()
 			return nil, errors.Wrap(err, indexErrMsg)
 		}
 		err = prepareGalaxyDB(galaxy)
 		if err != nil {
 			return nil, errors.Wrap(err, galaxyErrMsg)
 		}
+	} else {
+		err = index.View(checkIndexVersion)
+		if err != nil {
+			index.Close()
+			galaxy.Close()
+			return nil, errors.Wrap(err, indexErrMsg)
+		}
 	}
 
 	return &DB{index: index, galaxy: galaxy, input: make(chan dump.System)}, nil
@@ -87,7 +99,7 @@ func prepareIndexDB(db *bolt.DB) error {
 		if err != nil {
 			return errors.Wrap(err, "unable to create root bucket")
 		}
-		return nil
+		return setIndexVersion(tx)
 	})
 	if err != nil {
 		return errors.Wrap(err, "error preparing index DB buckets")
@@ -164,14 +176,7 @@ func (db *DB) consumer() {
 	go batchWriter(&db.wg, db.galaxy, errChan, galaxyChan, GalaxyBatchWriter)
 
 	for inputSystem := range db.input {
-		indexChan <- System{
-			ID64:        inputSystem.ID64,
-			X:           inputSystem.Coordinates.X,
-			Y:           inputSystem.Coordinates.Y,
-			Z:           inputSystem.Coordinates.Z,
-			IsNeutron:   NeutronInRange(inputSystem.Bodies),
-			IsScoopable: ScoopableInRange(inputSystem.Bodies),
-		}
+		indexChan <- store.NewSystem(inputSystem)
 		galaxyChan <- inputSystem
 	}
 
@@ -211,61 +216,3 @@ func batchWriter(wg *sync.WaitGroup, db *bolt.DB, errChan chan error, data chan
 		}
 	}
 }
-
-func NeutronInRange(bodies []dump.Body) bool {
-	// Anything within 1000ls is considered OK.
-	var maxDistance float64 = 1000
-	if bodies == nil || len(bodies) == 0 {
-		return false
-	}
-
-	for _, body := range bodies {
-		if body.Type != "Star" {
-			continue
-		}
-		// White dwarfs are not checked since they are considered "not worth it".
-		if body.SubType == "Neutron Star" {
-			if body.DistanceToArrival <= maxDistance {
-				return true
-			}
-		}
-	}
-
-	return false
-}
-
-func ScoopableInRange(bodies []dump.Body) bool {
-	// Anything within 1000ls is considered OK.
-	var maxDistance float64 = 1000
-	if bodies == nil || len(bodies) == 0 {
-		return false
-	}
-
-	for _, body := range bodies {
-		if body.Type != "Star" {
-			continue
-		}
-		switch body.SubType {
-		case
-			"A (Blue-White super giant) Star",
-			"A (Blue-White) Star",
-			"B (Blue-White super giant) Star",
-			"B (Blue-White) Star",
-			"F (White super giant) Star",
-			"F (White) Star",
-			"G (White-Yellow super giant) Star",
-			"G (White-Yellow) Star",
-			"K (Yellow-Orange giant) Star",
-			"K (Yellow-Orange) Star",
-			"M (Red dwarf) Star",
-			"M (Red giant) Star",
-			"M (Red super giant) Star",
-			"O (Blue-White) Star":
-			if body.DistanceToArrival <= maxDistance {
-				return true
-			}
-		}
-	}
-
-	return false
-}
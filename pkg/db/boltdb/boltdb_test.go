@@ -5,7 +5,9 @@ import (
 	"os"
 	"testing"
 
+	store "github.com/lunemec/ed-router/pkg/db"
 	"github.com/lunemec/ed-router/pkg/models/dump"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gonum.org/v1/gonum/spatial/r3"
@@ -43,6 +45,30 @@ func TestNeutronInRange(t *testing.T) {
 	assert.False(t, NeutronInRange([]dump.Body{{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 1000.1}}))
 }
 
+func TestNeutronDistance(t *testing.T) {
+	assert.EqualValues(t, 0, NeutronDistance([]dump.Body{{Type: "Star", SubType: "???", DistanceToArrival: 10}}))
+	assert.EqualValues(t, 0, NeutronDistance([]dump.Body{{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 1000.1}}))
+	assert.EqualValues(t, 12.5, NeutronDistance([]dump.Body{
+		{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 400},
+		{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 12.5},
+	}))
+}
+
+func TestWhiteDwarfInRange(t *testing.T) {
+	assert.True(t, WhiteDwarfInRange([]dump.Body{{Type: "Star", SubType: "White Dwarf (DA) Star", DistanceToArrival: 0}}))
+	assert.False(t, WhiteDwarfInRange([]dump.Body{{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 0}}))
+	assert.False(t, WhiteDwarfInRange([]dump.Body{{Type: "Star", SubType: "White Dwarf (D) Star", DistanceToArrival: 1000.1}}))
+	assert.False(t, NeutronInRange([]dump.Body{{Type: "Star", SubType: "White Dwarf (D) Star", DistanceToArrival: 0}}))
+}
+
+func TestWhiteDwarfDistance(t *testing.T) {
+	assert.EqualValues(t, 0, WhiteDwarfDistance([]dump.Body{{Type: "Star", SubType: "Neutron Star", DistanceToArrival: 10}}))
+	assert.EqualValues(t, 25, WhiteDwarfDistance([]dump.Body{
+		{Type: "Star", SubType: "White Dwarf (DAB) Star", DistanceToArrival: 300},
+		{Type: "Star", SubType: "White Dwarf (DC) Star", DistanceToArrival: 25},
+	}))
+}
+
 func TestScoopableInRange(t *testing.T) {
 	assert.True(t, ScoopableInRange([]dump.Body{{Type: "Star", SubType: "K (Yellow-Orange giant) Star", DistanceToArrival: 0}}))
 	assert.False(t, ScoopableInRange([]dump.Body{{Type: "Star", SubType: "K (Yellow-Orange giant) Star", DistanceToArrival: 1000.1}}))
@@ -98,11 +124,141 @@ func (t *BoltDBTestSuite) TestImport() {
 	}
 	t.db.StopInsert()
 
-	systems, err := t.db.PointsWithin(0, 0, 0, 0, 0, 0)
+	systems, err := t.db.PointsWithinXYZBuckets(0, 0, 0, 0, 0, 0)
 	t.NoError(err)
 	t.Len(systems, 2)
 	t.Contains(systems, System{ID64: 10477373803, X: 0, Y: 0, Z: 0, IsNeutron: false, IsScoopable: true})
 	t.Contains(systems, System{ID64: 10477373801, X: 0, Y: 0, Z: 0, IsNeutron: false, IsScoopable: false})
+
+	ids, err := t.db.SystemIDsByName("sol")
+	t.NoError(err)
+	t.Equal([]uint64{10477373803}, ids)
+	_, err = t.db.SystemIDsByName("So")
+	t.True(errors.Is(err, store.ErrNotFound))
+
+	ids, err = t.db.SystemIDsByPrefix("Extra 99")
+	t.NoError(err)
+	t.ElementsMatch([]uint64{99, 990, 991, 992, 993, 994, 995, 996, 997, 998, 999}, ids)
+
+	// Unknown names must not find the next name in order.
+	system, err := t.db.SystemByName("sol")
+	t.NoError(err)
+	t.Equal("Sol", system.Name)
+	_, err = t.db.SystemByName("So")
+	t.True(errors.Is(err, store.ErrNotFound))
+	_, err = t.db.SystemByName("Sol1")
+	t.True(errors.Is(err, store.ErrNotFound))
+	_, err = t.db.SystemByID(10477373802)
+	t.True(errors.Is(err, store.ErrNotFound))
+
+	names, err := t.db.SuggestNames("sol1", 5)
+	t.NoError(err)
+	t.Equal([]string{"Sol", "Sol2"}, names)
+	// Typo in the first letter.
+	names, err = t.db.SuggestNames("xol2", 5)
+	t.NoError(err)
+	t.Equal([]string{"Sol2", "Sol"}, names)
+	systems, err = t.db.Nearest(r3.Vec{X: 5.2, Y: 5.2, Z: 5.2}, 2, nil)
+	t.NoError(err)
+	t.Equal([]uint64{5, 6}, []uint64{systems[0].ID64, systems[1].ID64})
+	systems, err = t.db.WithinRadius(r3.Vec{}, 2, store.Scoopable)
+	t.NoError(err)
+	t.Len(systems, 1)
+	t.EqualValues(10477373803, systems[0].ID64)
+
+	names, err = t.db.SuggestNames("extra 99", 3)
+	t.NoError(err)
+	t.Equal([]string{"extra 990", "extra 991", "extra 992"}, names)
+}
+
+func (t *BoltDBTestSuite) TestNearest() {
+	// Systems on X axis, every 3rd one scoopable.
+	for i := 1; i <= 30; i++ {
+		s := dump.System{ID64: uint64(i), Name: fmt.Sprintf("nearest %d", i), Coordinates: r3.Vec{X: float64(i * i)}}
+		if i%3 == 0 {
+			s.Bodies = []dump.Body{{Type: "Star", SubType: "M (Red dwarf) Star"}}
+		}
+		t.NoError(t.db.InsertSystem(s))
+	}
+	t.db.StopInsert()
+
+	systems, err := t.db.WithinRadius(r3.Vec{X: 11}, 6, nil)
+	t.NoError(err)
+	t.Equal([]uint64{3, 4}, systemIDs(systems))
+	// Box corner is not within the radius.
+	systems, err = t.db.WithinRadius(r3.Vec{X: 4, Y: 5, Z: 5}, 5, nil)
+	t.NoError(err)
+	t.Empty(systems)
+	systems, err = t.db.WithinRadius(r3.Vec{X: 11}, 30, store.Scoopable)
+	t.NoError(err)
+	t.Equal([]uint64{3, 6}, systemIDs(systems))
+
+	systems, err = t.db.Nearest(r3.Vec{X: 11}, 2, nil)
+	t.NoError(err)
+	t.Equal([]uint64{3, 4}, systemIDs(systems))
+	// Found in several shells around the point, and on their sides.
+	systems, err = t.db.Nearest(r3.Vec{X: 500}, 3, store.Scoopable)
+	t.NoError(err)
+	t.Equal([]uint64{21, 24, 18}, systemIDs(systems))
+	systems, err = t.db.Nearest(r3.Vec{X: 500}, 40, nil)
+	t.NoError(err)
+	var all []uint64
+	for i := 1; i <= 30; i++ {
+		all = append(all, uint64(i))
+	}
+	t.ElementsMatch(all, systemIDs(systems))
+	// System 12 is on side of the cube searched before.
+	systems, err = t.db.Nearest(r3.Vec{X: 64}, 40, nil)
+	t.NoError(err)
+	t.ElementsMatch(all, systemIDs(systems))
+	systems, err = t.db.Nearest(r3.Vec{X: 160}, 1, nil)
+	t.NoError(err)
+	t.Equal([]uint64{13}, systemIDs(systems))
+	systems, err = t.db.Nearest(r3.Vec{}, 5, store.Neutron)
+	t.NoError(err)
+	t.Empty(systems)
+}
+
+func systemIDs(systems []System) []uint64 {
+	var ids []uint64
+	for _, s := range systems {
+		ids = append(ids, s.ID64)
+	}
+	return ids
+}
+
+func (t *BoltDBTestSuite) TestDuplicateNames() {
+	insert := []dump.System{
+		{ID64: 1, Name: "Hyades Sector AB-C d1", Coordinates: r3.Vec{X: 10}},
+		{ID64: 2, Name: "hyades sector ab-c d1", Coordinates: r3.Vec{X: 20}},
+		{ID64: 3, Name: "Other", Coordinates: r3.Vec{X: 30}},
+		// Importing the same system again does not duplicate it.
+		{ID64: 1, Name: "Hyades Sector AB-C d1", Coordinates: r3.Vec{X: 10}},
+	}
+	for _, s := range insert {
+		t.NoError(t.db.InsertSystem(s))
+	}
+	t.db.StopInsert()
+
+	ids, err := t.db.SystemIDsByName("Hyades Sector AB-C d1")
+	t.NoError(err)
+	t.Equal([]uint64{1, 2}, ids)
+
+	_, err = t.db.SystemByName("Hyades Sector AB-C d1")
+	var ambiguous *store.AmbiguousError
+	t.True(errors.As(err, &ambiguous))
+	t.Len(ambiguous.Candidates, 2)
+
+	ids, err = t.db.SystemIDsByPrefix("Hyades Sector ")
+	t.NoError(err)
+	t.Equal([]uint64{1, 2}, ids)
+}
+
+func TestMarshalIDs(t *testing.T) {
+	ids := []uint64{1, 10477373803, 2}
+	assert.Equal(t, ids, UnmarshalIDs(MarshalIDs(ids)))
+	// Databases imported with single ID64 per name read the same.
+	assert.Equal(t, []uint64{10477373803}, UnmarshalIDs(MarshalGalaxyKey(10477373803)))
 }
 
 func TestBoltDBTestSuite(t *testing.T) {
@@ -1,10 +1,12 @@
 package boltdb
 
 import (
+	"bytes"
 	"encoding/binary"
 	"strings"
 
 	jsoniter "github.com/json-iterator/go"
+	store "github.com/lunemec/ed-router/pkg/db"
 	"github.com/lunemec/ed-router/pkg/models/dump"
 	"github.com/pkg/errors"
 	bolt "go.etcd.io/bbolt"
@@ -48,6 +50,25 @@ func UnmarshalName(b []byte) string {
 	return string(b)
 }
 
+// MarshalIDs marshals ID64s of the systems sharing one name, value of
+// the names bucket.
+func MarshalIDs(ids []uint64) []byte {
+	b := make([]byte, 0, 8*len(ids))
+	for _, id64 := range ids {
+		b = append(b, MarshalGalaxyKey(id64)...)
+	}
+	return b
+}
+
+// UnmarshalIDs unmarshals ID64s of the systems sharing one name.
+func UnmarshalIDs(b []byte) []uint64 {
+	ids := make([]uint64, 0, len(b)/8)
+	for ; len(b) >= 8; b = b[8:] {
+		ids = append(ids, UnmarshalGalaxyKey(b))
+	}
+	return ids
+}
+
 func GalaxyBatchWriter(db *bolt.DB, batch []interface{}) error {
 	tx, err := db.Begin(true)
 	if err != nil {
@@ -87,8 +108,17 @@ func insertSystem(bucket *bolt.Bucket, id64 uint64, system dump.System) error {
 	return nil
 }
 
+// insertName adds id64 to the systems with the name, many systems in the
+// galaxy share the same name.
 func insertName(bucket *bolt.Bucket, name string, id64 uint64) error {
-	err := bucket.Put(MarshalName(name), MarshalGalaxyKey(id64))
+	key := MarshalName(name)
+	ids := UnmarshalIDs(bucket.Get(key))
+	for _, id := range ids {
+		if id == id64 {
+			return nil
+		}
+	}
+	err := bucket.Put(key, MarshalIDs(append(ids, id64)))
 	if err != nil {
 		return errors.Wrap(err, "unable to insert dimension coordinates to bucket")
 	}
@@ -103,9 +133,9 @@ func (db *DB) SystemByID(id64 uint64) (dump.System, error) {
 	err = db.galaxy.View(func(tx *bolt.Tx) error {
 		var err error
 
-		k, value := tx.Bucket(bucketSystems).Cursor().Seek(MarshalGalaxyKey(id64))
-		if k == nil {
-			return errors.Errorf("unable to find system by ID64: %d", id64)
+		value := tx.Bucket(bucketSystems).Get(MarshalGalaxyKey(id64))
+		if value == nil {
+			return errors.Wrapf(store.ErrNotFound, "unable to find system by ID64: %d", id64)
 		}
 		system, err = UnmarshalGalaxyValue(value)
 		if err != nil {
@@ -114,35 +144,108 @@ func (db *DB) SystemByID(id64 uint64) (dump.System, error) {
 		return nil
 	})
 	if err != nil {
-		return system, errors.Wrap(err, "unable to get system by name")
+		return system, errors.Wrap(err, "unable to get system by ID64")
 	}
 	return system, nil
 }
 
+// SystemByName returns the system with exactly the name, case
+// insensitive. Error is ErrNotFound when there is none and AmbiguousError
+// when there are more systems with the name.
 func (db *DB) SystemByName(name string) (dump.System, error) {
-	var (
-		err    error
-		system dump.System
-	)
-	err = db.galaxy.View(func(tx *bolt.Tx) error {
-		var err error
-
-		k, id64Bytes := tx.Bucket(bucketNames).Cursor().Seek(MarshalName(name))
-		if k == nil {
-			return errors.Errorf("unable to find name: %s", name)
+	ids, err := db.SystemIDsByName(name)
+	if err != nil {
+		return dump.System{}, errors.Wrap(err, "unable to get system by name")
+	}
+	var systems []dump.System
+	for _, id64 := range ids {
+		system, err := db.SystemByID(id64)
+		if err != nil {
+			return dump.System{}, errors.Wrap(err, "unable to get system by name")
 		}
-		k, value := tx.Bucket(bucketSystems).Cursor().Seek(id64Bytes)
-		if k == nil {
-			return errors.Errorf("unable to find system by ID64: %d", UnmarshalGalaxyKey(id64Bytes))
+		systems = append(systems, system)
+	}
+	if len(systems) > 1 {
+		return dump.System{}, &store.AmbiguousError{Name: name, Candidates: systems}
+	}
+	return systems[0], nil
+}
+
+// SystemIDsByName returns ID64s of all the systems with exactly the name,
+// case insensitive.
+func (db *DB) SystemIDsByName(name string) ([]uint64, error) {
+	var ids []uint64
+	err := db.galaxy.View(func(tx *bolt.Tx) error {
+		// Seek would find the next name when there is no such name.
+		v := tx.Bucket(bucketNames).Get(MarshalName(name))
+		if len(v) == 0 {
+			return errors.Wrapf(store.ErrNotFound, "unable to find name: %s", name)
 		}
-		system, err = UnmarshalGalaxyValue(value)
-		if err != nil {
-			return errors.Wrapf(err, "unable to unmarshal galaxy data for ID64: %d", UnmarshalGalaxyKey(id64Bytes))
+		ids = UnmarshalIDs(v)
+		return nil
+	})
+	return ids, err
+}
+
+// SystemIDsByPrefix returns ID64s of all the systems with name starting
+// with prefix, case insensitive.
+func (db *DB) SystemIDsByPrefix(prefix string) ([]uint64, error) {
+	var ids []uint64
+	err := db.galaxy.View(func(tx *bolt.Tx) error {
+		p := MarshalName(prefix)
+		c := tx.Bucket(bucketNames).Cursor()
+		for k, v := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = c.Next() {
+			ids = append(ids, UnmarshalIDs(v)...)
 		}
 		return nil
 	})
 	if err != nil {
-		return system, errors.Wrap(err, "unable to get system by name")
+		return nil, errors.Wrapf(err, "unable to get systems with prefix: %s", prefix)
 	}
-	return system, nil
+	return ids, nil
+}
+
+// SuggestNames returns at most limit names of systems similar to the
+// unknown name, see store.SuggestNames.
+func (db *DB) SuggestNames(name string, limit int) ([]string, error) {
+	suggested, err := store.SuggestNames(name, limit, func(from string, forward bool, add func(string) bool) error {
+		return db.galaxy.View(func(tx *bolt.Tx) error {
+			c := tx.Bucket(bucketNames).Cursor()
+			k, _ := c.Seek(MarshalName(from))
+			next := c.Next
+			if !forward {
+				// Seek stops after the name, or past the last one.
+				if k == nil {
+					k, _ = c.Last()
+				} else {
+					k, _ = c.Prev()
+				}
+				next = c.Prev
+			}
+			for ; k != nil; k, _ = next() {
+				if !add(UnmarshalName(k)) {
+					break
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to suggest names for: %s", name)
+	}
+
+	// Names are stored upper case, suggest them as they are in the galaxy.
+	var names []string
+	for _, upper := range suggested {
+		ids, err := db.SystemIDsByName(upper)
+		if err != nil {
+			return nil, err
+		}
+		system, err := db.SystemByID(ids[0])
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, system.Name)
+	}
+	return names, nil
 }
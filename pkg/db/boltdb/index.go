@@ -8,8 +8,10 @@ import (
 	"strings"
 	"sync"
 
+	store "github.com/lunemec/ed-router/pkg/db"
 	"github.com/pkg/errors"
 	bolt "go.etcd.io/bbolt"
+	"gonum.org/v1/gonum/spatial/r3"
 )
 
 // MarshalIndexKey marshals key of index database into []byte.
@@ -19,10 +21,15 @@ func MarshalIndexKey(f float64) []byte {
 	// We multiply f by 1000 to move the fractional part up 3 places.
 	// Then we round, and increment max int32 to get rid of negative
 	// numbers, so we would start at 0 with f equal to min int32.
-	binary.BigEndian.PutUint64(out, uint64(int64(f*1000)+math.MaxInt32))
+	binary.BigEndian.PutUint64(out, uint64(indexKey(f)+math.MaxInt32))
 	return out
 }
 
+// indexKey is f as compared by queries, with 3 fractional places.
+func indexKey(f float64) int64 {
+	return int64(f * 1000)
+}
+
 // UnmarshalIndexKey unmarshals key from index database into float64.
 // We do not use named type because that added 1.5 ns/op overhead.
 func UnmarshalIndexKey(b []byte) float64 {
@@ -70,6 +77,12 @@ func UnmarshalIndexValueSingle(b []byte) System {
 	s.IsNeutron = b[0] != 0
 	b = b[1:]
 	s.IsScoopable = b[0] != 0
+	b = b[1:]
+	s.NeutronDistance = math.Float64frombits(binary.BigEndian.Uint64(b))
+	b = b[8:]
+	s.IsWhiteDwarf = b[0] != 0
+	b = b[1:]
+	s.WhiteDwarfDistance = math.Float64frombits(binary.BigEndian.Uint64(b))
 
 	return s
 }
@@ -92,16 +105,18 @@ func UnmarshalIndexValue(b []byte) []System {
 		b = b[1:]
 		out[i].IsScoopable = b[0] != 0
 		b = b[1:]
+		out[i].NeutronDistance = math.Float64frombits(binary.BigEndian.Uint64(b))
+		b = b[8:]
+		out[i].IsWhiteDwarf = b[0] != 0
+		b = b[1:]
+		out[i].WhiteDwarfDistance = math.Float64frombits(binary.BigEndian.Uint64(b))
+		b = b[8:]
 	}
 	return out
 }
 
-type System struct {
-	ID64        uint64
-	X, Y, Z     float64
-	IsNeutron   bool
-	IsScoopable bool
-}
+// System is the indexed part of the system.
+type System = store.System
 
 func (db *DB) PointsWithin(minX, maxX, minY, maxY, minZ, maxZ float64) ([]System, error) {
 	var (
@@ -198,7 +213,7 @@ func (db *DB) PointsWithinXYZBuckets(minX, maxX, minY, maxY, minZ, maxZ float64)
 				yCur := yBucket.Cursor()
 
 				for yK, yV := yCur.Seek(minZB); yK != nil && yV != nil && bytes.Compare(yK, maxZB) <= 0; yK, yV = yCur.Next() {
-					out = append(out, UnmarshalIndexValueSingle(yV))
+					out = append(out, UnmarshalIndexValue(yV)...)
 				}
 			}
 		}
@@ -211,7 +226,7 @@ func (db *DB) PointsWithinXYZBuckets(minX, maxX, minY, maxY, minZ, maxZ float64)
 	return out, err
 }
 
-func (db *DB) PointsWithinXYZBucketsChan(minX, maxX, minY, maxY, minZ, maxZ float64, out chan System) error {
+func (db *DB) PointsWithinXYZBucketsChan(minX, maxX, minY, maxY, minZ, maxZ float64, out chan<- System) error {
 	var (
 		minXB = MarshalIndexKey(minX)
 		maxXB = MarshalIndexKey(maxX)
@@ -241,7 +256,9 @@ func (db *DB) PointsWithinXYZBucketsChan(minX, maxX, minY, maxY, minZ, maxZ floa
 				yCur := yBucket.Cursor()
 
 				for yK, yV := yCur.Seek(minZB); yK != nil && yV != nil && bytes.Compare(yK, maxZB) <= 0; yK, yV = yCur.Next() {
-					out <- UnmarshalIndexValueSingle(yV)
+					for _, system := range UnmarshalIndexValue(yV) {
+						out <- system
+					}
 				}
 			}
 		}
@@ -255,6 +272,17 @@ func (db *DB) PointsWithinXYZBucketsChan(minX, maxX, minY, maxY, minZ, maxZ floa
 	return err
 }
 
+// SystemsWithin returns all the systems in box between min and max.
+func (db *DB) SystemsWithin(min, max r3.Vec) ([]System, error) {
+	return db.PointsWithinXYZBuckets(min.X, max.X, min.Y, max.Y, min.Z, max.Z)
+}
+
+// StreamSystemsWithin sends all the systems in box between min and max
+// to out, out is closed when done.
+func (db *DB) StreamSystemsWithin(min, max r3.Vec, out chan<- System) error {
+	return db.PointsWithinXYZBucketsChan(min.X, max.X, min.Y, max.Y, min.Z, max.Z, out)
+}
+
 func (db *DB) PointsWithinConcurrent(minX, maxX, minY, maxY, minZ, maxZ float64) ([]System, error) {
 	var (
 		out     []System
@@ -422,7 +450,9 @@ func IndexBatchWriterXYZBuckets(db *bolt.DB, batch []interface{}) error {
 		if err != nil {
 			return errors.Wrap(err, "error creating Y coord bucket under X bucket")
 		}
-		err = ybucket.Put(MarshalIndexKey(system.Z), MarshalIndexValueSingle(system))
+		// Multiple systems may share the same coordinates, so Z key
+		// holds list of systems.
+		err = insertSystemToDimension(ybucket, system.Z, []System{system})
 		if err != nil {
 			return errors.Wrap(err, "error creating Z key under Y bucket")
 		}
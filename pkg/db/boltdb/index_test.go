@@ -59,8 +59,11 @@ func TestIndexValueMarshal(t *testing.T) {
 		0x40, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item X float64
 		0x40, 0x8, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item Y float64
 		0x40, 0x10, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item Z float64
-		0x1, // 1st item IsNeutron bool
-		0x1, // 1st item IsScoopable bool
+		0x1,                                    // 1st item IsNeutron bool
+		0x1,                                    // 1st item IsScoopable bool
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item NeutronDistance float64
+		0x0,                                    // 1st item IsWhiteDwarf bool
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item WhiteDwarfDistance float64
 	}
 
 	assert.Equal(t, expect, res)
@@ -73,8 +76,11 @@ func TestIndexValueUnmarshal(t *testing.T) {
 		0x40, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item X float64
 		0x40, 0x8, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item Y float64
 		0x40, 0x10, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item Z float64
-		0x1, // 1st item IsNeutron bool
-		0x1, // 1st item IsScoopable bool
+		0x1,                                    // 1st item IsNeutron bool
+		0x1,                                    // 1st item IsScoopable bool
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item NeutronDistance float64
+		0x0,                                    // 1st item IsWhiteDwarf bool
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item WhiteDwarfDistance float64
 	}
 	expect := []System{
 		{
@@ -97,8 +103,11 @@ func TestIndexValueUnmarshalSol(t *testing.T) {
 		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item X float64
 		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item Y float64
 		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item Z float64
-		0x0, // 1st item IsNeutron bool
-		0x1, // 1st item IsScoopable bool
+		0x0,                                    // 1st item IsNeutron bool
+		0x1,                                    // 1st item IsScoopable bool
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item NeutronDistance float64
+		0x0,                                    // 1st item IsWhiteDwarf bool
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // 1st item WhiteDwarfDistance float64
 	}
 
 	expect := []System{
@@ -153,18 +162,27 @@ func TestIndexValueMarshalUnmarshalMultiple(t *testing.T) {
 		0x40, 0x10, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 		0x1,
 		0x1,
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
+		0x0,
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x3, 0xe7,
 		0x40, 0xa1, 0x74, 0x77, 0xce, 0xd9, 0x16, 0x87,
 		0x40, 0x8, 0xfb, 0xe7, 0x6c, 0x8b, 0x43, 0x96,
 		0x40, 0x12, 0x38, 0x51, 0xeb, 0x85, 0x1e, 0xb8,
 		0x0,
 		0x1,
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
+		0x0,
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x2c,
 		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 		0x0,
 		0x0,
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
+		0x0,
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 	}
 
 	assert.Equal(t, expectData, data)
@@ -184,18 +202,21 @@ func BenchmarkUnmarshalIndexValue(b *testing.B) {
 		0x40, 0x10, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 		0x1,
 		0x1,
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x3, 0xe7,
 		0x40, 0xa1, 0x74, 0x77, 0xce, 0xd9, 0x16, 0x87,
 		0x40, 0x8, 0xfb, 0xe7, 0x6c, 0x8b, 0x43, 0x96,
 		0x40, 0x12, 0x38, 0x51, 0xeb, 0x85, 0x1e, 0xb8,
 		0x0,
 		0x1,
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x2c,
 		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 		0x0,
 		0x0,
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 	}
 
 	expect := []System{
@@ -324,7 +345,7 @@ func (t *BoltDBTestSuite) TestNestedBuckets() {
 			if err != nil {
 				return err
 			}
-			err = ybucket.Put(MarshalIndexKey(system.Z), MarshalIndexValueSingle(system))
+			err = ybucket.Put(MarshalIndexKey(system.Z), MarshalIndexValue([]System{system}))
 			return err
 		})
 		t.NoError(err)
@@ -530,7 +551,7 @@ func BenchmarkBoltDBPointsWithinXYZBuckets(b *testing.B) {
 			if err != nil {
 				return err
 			}
-			err = ybucket.Put(MarshalIndexKey(system.Z), MarshalIndexValueSingle(system))
+			err = ybucket.Put(MarshalIndexKey(system.Z), MarshalIndexValue([]System{system}))
 			return err
 		})
 		assert.NoError(b, err)
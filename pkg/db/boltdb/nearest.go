@@ -0,0 +1,160 @@
+package boltdb
+
+import (
+	"sort"
+
+	store "github.com/lunemec/ed-router/pkg/db"
+	"github.com/lunemec/ed-router/pkg/distance"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+const (
+	// nearestMinRadius is the first radius nearest systems are searched in.
+	nearestMinRadius = 10.0
+	// nearestMaxRadius is more than size of the galaxy.
+	nearestMaxRadius = 200000.0
+)
+
+// box is part of the space between min and max.
+type box struct {
+	min, max r3.Vec
+}
+
+// contains is true when v is in the box or on its side, the same as for
+// box query.
+func (b box) contains(v r3.Vec) bool {
+	return between(v.X, b.min.X, b.max.X) &&
+		between(v.Y, b.min.Y, b.max.Y) &&
+		between(v.Z, b.min.Z, b.max.Z)
+}
+
+func between(f, min, max float64) bool {
+	k := indexKey(f)
+	return k >= indexKey(min) && k <= indexKey(max)
+}
+
+// cube returns box around point with half side r.
+func cube(point r3.Vec, r float64) box {
+	v := r3.Vec{X: r, Y: r, Z: r}
+	return box{min: point.Sub(v), max: point.Add(v)}
+}
+
+// WithinRadius returns systems selected by filter within radius LY of
+// point, the closest first.
+func (db *DB) WithinRadius(point r3.Vec, radius float64, filter store.Filter) ([]System, error) {
+	var out []System
+	err := db.streamBoxes(shell(point, 0, radius), func(s System) {
+		// Box query returns the box corners too.
+		if distance.Distance(point, s.Coordinates()) <= radius && selects(filter, s) {
+			out = append(out, s)
+		}
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to find systems near %g,%g,%g", point.X, point.Y, point.Z)
+	}
+	sortByDistance(point, out)
+	return out, nil
+}
+
+// Nearest returns at most k systems selected by filter nearest to point,
+// the closest first. It searches shells around the point growing until
+// there are k systems in the sphere already searched, every shell is read
+// only once.
+func (db *DB) Nearest(point r3.Vec, k int, filter store.Filter) ([]System, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+	var found []System
+	for inner, outer := 0.0, nearestMinRadius; inner < nearestMaxRadius; inner, outer = outer, outer*2 {
+		boxes := shell(point, inner, outer)
+		// Boxes share their sides with the cube searched by the previous
+		// shells and with the previous boxes of the shell, systems on the
+		// sides are taken from the first box only.
+		searched := []box{cube(point, inner)}
+		if inner <= 0 {
+			searched = nil
+		}
+		for _, b := range boxes {
+			err := db.streamBoxes([]box{b}, func(s System) {
+				for _, prev := range searched {
+					if prev.contains(s.Coordinates()) {
+						return
+					}
+				}
+				if selects(filter, s) {
+					found = append(found, s)
+				}
+			})
+			if err != nil {
+				return nil, errors.Wrapf(err, "unable to find systems near %g,%g,%g", point.X, point.Y, point.Z)
+			}
+			searched = append(searched, b)
+		}
+
+		// Systems outside of the sphere, but in its box, may be farther
+		// than systems in the next shell.
+		sortByDistance(point, found)
+		within := sort.Search(len(found), func(i int) bool {
+			return distance.Distance(point, found[i].Coordinates()) > outer
+		})
+		if within >= k {
+			return found[:k], nil
+		}
+	}
+	if len(found) > k {
+		found = found[:k]
+	}
+	return found, nil
+}
+
+// streamBoxes calls visit with all the systems in the boxes.
+func (db *DB) streamBoxes(boxes []box, visit func(System)) error {
+	for _, b := range boxes {
+		out := make(chan System, 1000)
+		errChan := make(chan error, 1)
+		go func() {
+			errChan <- db.StreamSystemsWithin(b.min, b.max, out)
+		}()
+		for s := range out {
+			visit(s)
+		}
+		if err := <-errChan; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shell returns boxes around point covering cube with half side outer,
+// except of the cube with half side inner already searched.
+func shell(point r3.Vec, inner, outer float64) []box {
+	if inner <= 0 {
+		return []box{cube(point, outer)}
+	}
+	o, i := cube(point, outer), cube(point, inner)
+	min, max := o.min, o.max
+	innerMin, innerMax := i.min, i.max
+	return []box{
+		// Whole sides of the cube on X axis.
+		{min: min, max: r3.Vec{X: innerMin.X, Y: max.Y, Z: max.Z}},
+		{min: r3.Vec{X: innerMax.X, Y: min.Y, Z: min.Z}, max: max},
+		// Sides on Y axis between the X sides.
+		{min: r3.Vec{X: innerMin.X, Y: min.Y, Z: min.Z}, max: r3.Vec{X: innerMax.X, Y: innerMin.Y, Z: max.Z}},
+		{min: r3.Vec{X: innerMin.X, Y: innerMax.Y, Z: min.Z}, max: r3.Vec{X: innerMax.X, Y: max.Y, Z: max.Z}},
+		// Sides on Z axis between all the others.
+		{min: r3.Vec{X: innerMin.X, Y: innerMin.Y, Z: min.Z}, max: r3.Vec{X: innerMax.X, Y: innerMax.Y, Z: innerMin.Z}},
+		{min: r3.Vec{X: innerMin.X, Y: innerMin.Y, Z: innerMax.Z}, max: r3.Vec{X: innerMax.X, Y: innerMax.Y, Z: max.Z}},
+	}
+}
+
+func selects(filter store.Filter, s System) bool {
+	return filter == nil || filter(s)
+}
+
+func sortByDistance(point r3.Vec, systems []System) {
+	sort.SliceStable(systems, func(i, j int) bool {
+		return distance.Distance(point, systems[i].Coordinates()) < distance.Distance(point, systems[j].Coordinates())
+	})
+}
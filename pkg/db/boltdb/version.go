@@ -0,0 +1,64 @@
+package boltdb
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// IndexVersion is version of the index DB format, databases of other
+// versions must be imported again. Versions are:
+//
+//	1: single system per Z key, without version key.
+//	2: list of systems per Z key, with neutron star distance.
+//	3: with white dwarf star and its distance.
+const IndexVersion = 3
+
+var (
+	bucketMeta = []byte("meta")
+	keyVersion = []byte("version")
+)
+
+// ErrIndexVersion is returned when the index DB has different format
+// version than IndexVersion.
+var ErrIndexVersion = errors.New("index database format is not supported, delete it and re-run import")
+
+// indexVersion returns format version of the index DB.
+func indexVersion(tx *bolt.Tx) uint64 {
+	meta := tx.Bucket(bucketMeta)
+	if meta == nil {
+		return 1
+	}
+	v := meta.Get(keyVersion)
+	if len(v) != 8 {
+		return 1
+	}
+	return binary.BigEndian.Uint64(v)
+}
+
+// checkIndexVersion returns ErrIndexVersion when the index DB is not
+// in the IndexVersion format.
+func checkIndexVersion(tx *bolt.Tx) error {
+	version := indexVersion(tx)
+	if version != IndexVersion {
+		return errors.Wrapf(ErrIndexVersion, "found version %d, need version %d", version, IndexVersion)
+	}
+	return nil
+}
+
+// setIndexVersion marks the index DB with IndexVersion. Empty databases
+// are marked, systems are not added to databases of other version.
+func setIndexVersion(tx *bolt.Tx) error {
+	root := tx.Bucket(bucketRoot)
+	if root != nil {
+		if k, _ := root.Cursor().First(); k != nil {
+			return checkIndexVersion(tx)
+		}
+	}
+	meta, err := tx.CreateBucketIfNotExists(bucketMeta)
+	if err != nil {
+		return errors.Wrap(err, "unable to create meta bucket")
+	}
+	return meta.Put(keyVersion, MarshalGalaxyKey(IndexVersion))
+}
@@ -0,0 +1,44 @@
+package boltdb
+
+import (
+	"os"
+
+	"github.com/lunemec/ed-router/pkg/models/dump"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func (t *BoltDBTestSuite) TestIndexVersion() {
+	t.NoError(t.db.InsertSystem(dump.System{ID64: 1, Name: "Sol", Coordinates: r3.Vec{}}))
+	t.db.StopInsert()
+	t.NoError(t.db.Close())
+
+	db, err := Open(testIndexFile, testGalaxyFile, true)
+	t.NoError(err)
+	t.NoError(db.Close())
+
+	// Index imported before the format was versioned.
+	index, err := bolt.Open(testIndexFile, 0666, nil)
+	t.Require().NoError(err)
+	t.NoError(index.Update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket(bucketMeta)
+	}))
+	t.NoError(index.Close())
+
+	_, err = Open(testIndexFile, testGalaxyFile, true)
+	t.True(errors.Is(err, ErrIndexVersion))
+	t.Contains(err.Error(), "re-run import")
+	// Importing more systems to it would mix the formats.
+	_, err = Open(testIndexFile, testGalaxyFile, false)
+	t.True(errors.Is(err, ErrIndexVersion))
+
+	t.db, err = Open(testIndexFile+".new", testGalaxyFile+".new", false)
+	t.Require().NoError(err)
+	t.NoError(t.db.Close())
+	t.db, err = Open(testIndexFile+".new", testGalaxyFile+".new", true)
+	t.NoError(err)
+	t.NoError(os.Remove(testIndexFile + ".new"))
+	t.NoError(os.Remove(testGalaxyFile + ".new"))
+}
@@ -0,0 +1,23 @@
+package db
+
+import (
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// Filter selects systems for the queries, nil Filter selects all.
+type Filter func(s System) bool
+
+// Scoopable selects systems with scoopable star.
+func Scoopable(s System) bool {
+	return s.IsScoopable
+}
+
+// Neutron selects systems with neutron star.
+func Neutron(s System) bool {
+	return s.IsNeutron
+}
+
+// Coordinates returns coordinates of the system in LY.
+func (s System) Coordinates() r3.Vec {
+	return r3.Vec{X: s.X, Y: s.Y, Z: s.Z}
+}
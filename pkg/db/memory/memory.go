@@ -0,0 +1,233 @@
+// Package memory is in-memory SystemStore, for tests and small data sets.
+package memory
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/lunemec/ed-router/pkg/db"
+	"github.com/lunemec/ed-router/pkg/distance"
+	"github.com/lunemec/ed-router/pkg/models/dump"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+var _ db.SystemStore = (*Store)(nil)
+
+// Store keeps all the systems in memory, queries scan all of them.
+type Store struct {
+	systems []dump.System
+	index   []db.System
+	byID    map[uint64]int
+	// byName are indexes of the systems with the name, names are not
+	// unique.
+	byName map[string][]int
+}
+
+// New returns store of the systems.
+func New(systems ...dump.System) *Store {
+	s := &Store{
+		byID:   make(map[uint64]int),
+		byName: make(map[string][]int),
+	}
+	for _, system := range systems {
+		s.Insert(system)
+	}
+	return s
+}
+
+// Insert adds the system to the store, system with the same ID64 is
+// replaced.
+func (s *Store) Insert(system dump.System) {
+	i, ok := s.byID[system.ID64]
+	if !ok {
+		i = len(s.systems)
+		s.systems = append(s.systems, dump.System{})
+		s.index = append(s.index, db.System{})
+		s.byID[system.ID64] = i
+	} else {
+		s.removeName(i)
+	}
+	s.systems[i] = system
+	s.index[i] = db.NewSystem(system)
+	n := name(system.Name)
+	s.byName[n] = append(s.byName[n], i)
+}
+
+// removeName removes system at index i from systems with its name.
+func (s *Store) removeName(i int) {
+	n := name(s.systems[i].Name)
+	indexes := s.byName[n][:0]
+	for _, j := range s.byName[n] {
+		if j != i {
+			indexes = append(indexes, j)
+		}
+	}
+	if len(indexes) == 0 {
+		delete(s.byName, n)
+		return
+	}
+	s.byName[n] = indexes
+}
+
+// name is the key systems are looked up by, names are case insensitive.
+func name(s string) string {
+	return strings.ToUpper(s)
+}
+
+// SystemByID returns full system with ID64.
+func (s *Store) SystemByID(id64 uint64) (dump.System, error) {
+	i, ok := s.byID[id64]
+	if !ok {
+		return dump.System{}, errors.Wrapf(db.ErrNotFound, "unable to find system by ID64: %d", id64)
+	}
+	return s.systems[i], nil
+}
+
+// SystemByName returns the system with exactly the name, case
+// insensitive.
+func (s *Store) SystemByName(n string) (dump.System, error) {
+	indexes, ok := s.byName[name(n)]
+	if !ok {
+		return dump.System{}, errors.Wrapf(db.ErrNotFound, "unable to find name: %s", n)
+	}
+	if len(indexes) > 1 {
+		var candidates []dump.System
+		for _, i := range indexes {
+			candidates = append(candidates, s.systems[i])
+		}
+		return dump.System{}, &db.AmbiguousError{Name: n, Candidates: candidates}
+	}
+	return s.systems[indexes[0]], nil
+}
+
+// SystemIDsByName returns ID64s of all the systems with exactly the name,
+// case insensitive.
+func (s *Store) SystemIDsByName(n string) ([]uint64, error) {
+	indexes, ok := s.byName[name(n)]
+	if !ok {
+		return nil, errors.Wrapf(db.ErrNotFound, "unable to find name: %s", n)
+	}
+	var ids []uint64
+	for _, i := range indexes {
+		ids = append(ids, s.systems[i].ID64)
+	}
+	return ids, nil
+}
+
+// SystemIDsByPrefix returns ID64s of all the systems with name starting
+// with prefix, case insensitive, ordered by name.
+func (s *Store) SystemIDsByPrefix(prefix string) ([]uint64, error) {
+	var names []string
+	prefix = name(prefix)
+	for n := range s.byName {
+		if strings.HasPrefix(n, prefix) {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+
+	var ids []uint64
+	for _, n := range names {
+		for _, i := range s.byName[n] {
+			ids = append(ids, s.systems[i].ID64)
+		}
+	}
+	return ids, nil
+}
+
+// SuggestNames returns at most limit names of systems similar to the
+// unknown name, see db.SuggestNames.
+func (s *Store) SuggestNames(n string, limit int) ([]string, error) {
+	suggested, err := db.SuggestNames(n, limit, func(from string, forward bool, add func(string) bool) error {
+		names := make([]string, 0, len(s.byName))
+		for n := range s.byName {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		i := sort.SearchStrings(names, from)
+		if forward {
+			for ; i < len(names) && add(names[i]); i++ {
+			}
+			return nil
+		}
+		for i--; i >= 0 && add(names[i]); i-- {
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Names are kept upper case, suggest them as they are in the store.
+	var names []string
+	for _, upper := range suggested {
+		names = append(names, s.systems[s.byName[upper][0]].Name)
+	}
+	return names, nil
+}
+
+// SystemsWithin returns all the systems in box between min and max.
+func (s *Store) SystemsWithin(min, max r3.Vec) ([]db.System, error) {
+	var out []db.System
+	for _, system := range s.index {
+		if within(system, min, max) {
+			out = append(out, system)
+		}
+	}
+	return out, nil
+}
+
+// StreamSystemsWithin sends all the systems in box between min and max
+// to out, out is closed when done.
+func (s *Store) StreamSystemsWithin(min, max r3.Vec, out chan<- db.System) error {
+	defer close(out)
+	for _, system := range s.index {
+		if within(system, min, max) {
+			out <- system
+		}
+	}
+	return nil
+}
+
+// Nearest returns at most k systems selected by filter nearest to point,
+// the closest first.
+func (s *Store) Nearest(point r3.Vec, k int, filter db.Filter) ([]db.System, error) {
+	if k <= 0 {
+		return nil, nil
+	}
+	out := s.selected(point, math.Inf(1), filter)
+	if len(out) > k {
+		out = out[:k]
+	}
+	return out, nil
+}
+
+// WithinRadius returns systems selected by filter within radius LY of
+// point, the closest first.
+func (s *Store) WithinRadius(point r3.Vec, radius float64, filter db.Filter) ([]db.System, error) {
+	return s.selected(point, radius, filter), nil
+}
+
+// selected returns systems selected by filter within radius LY of point,
+// the closest first.
+func (s *Store) selected(point r3.Vec, radius float64, filter db.Filter) []db.System {
+	var out []db.System
+	for _, system := range s.index {
+		if distance.Distance(point, system.Coordinates()) <= radius && (filter == nil || filter(system)) {
+			out = append(out, system)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return distance.Distance(point, out[i].Coordinates()) < distance.Distance(point, out[j].Coordinates())
+	})
+	return out
+}
+
+func within(s db.System, min, max r3.Vec) bool {
+	return s.X >= min.X && s.X <= max.X &&
+		s.Y >= min.Y && s.Y <= max.Y &&
+		s.Z >= min.Z && s.Z <= max.Z
+}
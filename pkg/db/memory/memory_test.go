@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/lunemec/ed-router/pkg/db"
+	"github.com/lunemec/ed-router/pkg/models/dump"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestStore(t *testing.T) {
+	s := New(
+		dump.System{ID64: 1, Name: "Sol", Coordinates: r3.Vec{X: 0}},
+		dump.System{ID64: 3, Name: "Col 285 Sector B", Coordinates: r3.Vec{X: 20}},
+		dump.System{ID64: 2, Name: "Col 285 Sector A", Coordinates: r3.Vec{X: 10}, Bodies: []dump.Body{
+			{Type: "Star", SubType: "Neutron Star"},
+		}},
+	)
+
+	system, err := s.SystemByName("sol")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, system.ID64)
+	_, err = s.SystemByName("Sol2")
+	assert.Error(t, err)
+	_, err = s.SystemByID(4)
+	assert.Error(t, err)
+
+	names, err := s.SuggestNames("col 285 sector", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Col 285 Sector A", "Col 285 Sector B"}, names)
+	// Typo in the first letters.
+	names, err = s.SuggestNames("Sil", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sol"}, names)
+	names, err = s.SuggestNames("Maia", 5)
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+
+	ids, err := s.SystemIDsByPrefix("col 285 sector ")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3}, ids)
+
+	systems, err := s.SystemsWithin(r3.Vec{X: 5, Y: -1, Z: -1}, r3.Vec{X: 10, Y: 1, Z: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []db.System{{ID64: 2, X: 10, IsNeutron: true}}, systems)
+
+	out := make(chan db.System, 3)
+	assert.NoError(t, s.StreamSystemsWithin(r3.Vec{X: -1, Y: -1, Z: -1}, r3.Vec{X: 30, Y: 1, Z: 1}, out))
+	var streamed int
+	for range out {
+		streamed++
+	}
+	assert.Equal(t, 3, streamed)
+}
+
+func TestNearest(t *testing.T) {
+	s := New()
+	// Every 3rd system is scoopable.
+	for i := 1; i <= 30; i++ {
+		system := dump.System{ID64: uint64(i), Coordinates: r3.Vec{X: float64(i * i)}}
+		if i%3 == 0 {
+			system.Bodies = []dump.Body{{Type: "Star", SubType: "M (Red dwarf) Star"}}
+		}
+		s.Insert(system)
+	}
+
+	systems, err := s.Nearest(r3.Vec{X: 11}, 2, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3, 4}, ids(systems))
+
+	systems, err = s.Nearest(r3.Vec{X: 500}, 3, db.Scoopable)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{21, 24, 18}, ids(systems))
+
+	systems, err = s.Nearest(r3.Vec{}, 5, db.Neutron)
+	assert.NoError(t, err)
+	assert.Empty(t, systems)
+
+	systems, err = s.WithinRadius(r3.Vec{X: 100}, 40, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{10, 9, 11, 8}, ids(systems))
+	systems, err = s.WithinRadius(r3.Vec{X: 100}, 40, db.Scoopable)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{9}, ids(systems))
+}
+
+func ids(systems []db.System) []uint64 {
+	var ids []uint64
+	for _, s := range systems {
+		ids = append(ids, s.ID64)
+	}
+	return ids
+}
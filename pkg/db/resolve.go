@@ -0,0 +1,130 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lunemec/ed-router/pkg/distance"
+	"github.com/lunemec/ed-router/pkg/models/dump"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// AmbiguousError is returned when more systems share the name.
+type AmbiguousError struct {
+	Name       string
+	Candidates []dump.System
+}
+
+func (e *AmbiguousError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "there are %d systems named %s, use one of:", len(e.Candidates), e.Name)
+	for _, c := range e.Candidates {
+		fmt.Fprintf(&b, "\n  %s@%d at %g,%g,%g", c.Name, c.ID64, c.Coordinates.X, c.Coordinates.Y, c.Coordinates.Z)
+	}
+	return b.String()
+}
+
+const (
+	// id64Prefix is prefix of system given by ID64, eg. "id64:10477373803".
+	id64Prefix = "id64:"
+	// xyzPrefix is prefix of coordinates in LY, the nearest system to them
+	// is used, eg. "xyz:-9530.5,-910.3,19808.1".
+	xyzPrefix = "xyz:"
+)
+
+// Resolve returns the system user means by query, which is system name,
+// optionally followed by the ID64 or coordinates of the system when
+// more systems share the name: "Name@id64" or "Name@x,y,z". Query may
+// also be "id64:<ID64>" or "xyz:<x,y,z>" for the system nearest to the
+// coordinates.
+func Resolve(store SystemStore, query string) (dump.System, error) {
+	switch {
+	case strings.HasPrefix(query, id64Prefix):
+		id64, err := strconv.ParseUint(strings.TrimPrefix(query, id64Prefix), 10, 64)
+		if err != nil {
+			return dump.System{}, errors.Wrapf(err, "invalid ID64: %s", query)
+		}
+		return store.SystemByID(id64)
+	case strings.HasPrefix(query, xyzPrefix):
+		coordinates, err := parseCoordinates(strings.TrimPrefix(query, xyzPrefix))
+		if err != nil {
+			return dump.System{}, errors.Wrapf(err, "invalid coordinates: %s", query)
+		}
+		nearest, err := store.Nearest(coordinates, 1, nil)
+		if err != nil {
+			return dump.System{}, err
+		}
+		if len(nearest) == 0 {
+			return dump.System{}, errors.Wrapf(ErrNotFound, "unable to find any system near %s", query)
+		}
+		return store.SystemByID(nearest[0].ID64)
+	}
+
+	i := strings.LastIndex(query, "@")
+	if i < 0 {
+		system, err := store.SystemByName(query)
+		if errors.Is(err, ErrNotFound) {
+			return system, NotFound(store, query)
+		}
+		return system, err
+	}
+
+	name, which := query[:i], query[i+1:]
+	ids, err := store.SystemIDsByName(name)
+	if errors.Is(err, ErrNotFound) {
+		return dump.System{}, NotFound(store, name)
+	}
+	if err != nil {
+		return dump.System{}, err
+	}
+
+	if id64, err := strconv.ParseUint(which, 10, 64); err == nil {
+		for _, id := range ids {
+			if id == id64 {
+				return store.SystemByID(id64)
+			}
+		}
+		return dump.System{}, errors.Wrapf(ErrNotFound, "unable to find system %s with ID64: %d", name, id64)
+	}
+
+	coordinates, err := parseCoordinates(which)
+	if err != nil {
+		return dump.System{}, errors.Wrapf(err, "invalid system %q, use Name, Name@id64 or Name@x,y,z", query)
+	}
+	// Coordinates need not be exact, the closest system is used.
+	var (
+		closest     dump.System
+		closestDist float64
+	)
+	for i, id64 := range ids {
+		system, err := store.SystemByID(id64)
+		if err != nil {
+			return dump.System{}, err
+		}
+		d := distance.Distance(coordinates, system.Coordinates)
+		if i == 0 || d < closestDist {
+			closest, closestDist = system, d
+		}
+	}
+	return closest, nil
+}
+
+// parseCoordinates parses "x,y,z" coordinates in LY.
+func parseCoordinates(s string) (r3.Vec, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 3 {
+		return r3.Vec{}, errors.Errorf("expected x,y,z coordinates, got: %s", s)
+	}
+	var xyz [3]float64
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return r3.Vec{}, errors.Wrapf(err, "invalid coordinate: %s", part)
+		}
+		xyz[i] = f
+	}
+	return r3.Vec{X: xyz[0], Y: xyz[1], Z: xyz[2]}, nil
+}
@@ -0,0 +1,76 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/lunemec/ed-router/pkg/db"
+	"github.com/lunemec/ed-router/pkg/db/memory"
+	"github.com/lunemec/ed-router/pkg/models/dump"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestResolve(t *testing.T) {
+	store := memory.New(
+		dump.System{ID64: 1, Name: "Sol", Coordinates: r3.Vec{}},
+		dump.System{ID64: 2, Name: "Twin", Coordinates: r3.Vec{X: -10, Y: 2.5}},
+		dump.System{ID64: 3, Name: "twin", Coordinates: r3.Vec{X: 100}},
+	)
+
+	system, err := db.Resolve(store, "sol")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, system.ID64)
+
+	_, err = db.Resolve(store, "Twin")
+	var ambiguous *db.AmbiguousError
+	assert.True(t, errors.As(err, &ambiguous))
+	assert.Equal(t, "there are 2 systems named Twin, use one of:\n  Twin@2 at -10,2.5,0\n  twin@3 at 100,0,0", err.Error())
+
+	system, err = db.Resolve(store, "Twin@3")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, system.ID64)
+	_, err = db.Resolve(store, "Twin@1")
+	assert.True(t, errors.Is(err, db.ErrNotFound))
+
+	// Coordinates need not be exact.
+	system, err = db.Resolve(store, "twin@-9.5, 2, 0")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, system.ID64)
+
+	_, err = db.Resolve(store, "Twin@1,2")
+	assert.Error(t, err)
+	_, err = db.Resolve(store, "Twim@3")
+	assert.True(t, errors.Is(err, db.ErrNotFound))
+	assert.Contains(t, err.Error(), "did you mean: Twin?")
+}
+
+func TestResolveID64AndCoordinates(t *testing.T) {
+	store := memory.New(
+		dump.System{ID64: 1, Name: "Corner", Coordinates: r3.Vec{X: 9, Y: 9}},
+		dump.System{ID64: 2, Name: "Side", Coordinates: r3.Vec{X: 11}},
+		dump.System{ID64: 3, Name: "Far", Coordinates: r3.Vec{X: -9530.5, Y: -910.3, Z: 19808.1}},
+	)
+
+	system, err := db.Resolve(store, "id64:3")
+	assert.NoError(t, err)
+	assert.Equal(t, "Far", system.Name)
+	_, err = db.Resolve(store, "id64:4")
+	assert.True(t, errors.Is(err, db.ErrNotFound))
+	_, err = db.Resolve(store, "id64:far")
+	assert.Error(t, err)
+
+	// Corner is in the first box searched, but Side is closer.
+	system, err = db.Resolve(store, "xyz:0,0,0")
+	assert.NoError(t, err)
+	assert.Equal(t, "Side", system.Name)
+	system, err = db.Resolve(store, "xyz:-9000,-900,19000")
+	assert.NoError(t, err)
+	assert.Equal(t, "Far", system.Name)
+	_, err = db.Resolve(store, "xyz:0,0")
+	assert.Error(t, err)
+
+	_, err = db.Resolve(memory.New(), "xyz:0,0,0")
+	assert.True(t, errors.Is(err, db.ErrNotFound))
+}
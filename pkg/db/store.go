@@ -0,0 +1,72 @@
+// Package db defines storage of the galaxy systems the router searches.
+package db
+
+import (
+	"github.com/lunemec/ed-router/pkg/models/dump"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// ErrNotFound is returned when there is no system with the name or ID64.
+var ErrNotFound = errors.New("system not found")
+
+// SystemStore is storage of the galaxy systems.
+type SystemStore interface {
+	// SystemByID returns full system with ID64, error is ErrNotFound
+	// when there is none.
+	SystemByID(id64 uint64) (dump.System, error)
+	// SystemByName returns full system with exactly the name, case
+	// insensitive, error is ErrNotFound when there is none and
+	// AmbiguousError when there are more systems with the name.
+	SystemByName(name string) (dump.System, error)
+	// SystemIDsByName returns ID64s of all the systems with exactly the
+	// name, case insensitive, error is ErrNotFound when there is none.
+	SystemIDsByName(name string) ([]uint64, error)
+	// SystemIDsByPrefix returns ID64s of all the systems with name
+	// starting with prefix, case insensitive.
+	SystemIDsByPrefix(prefix string) ([]uint64, error)
+	// SuggestNames returns at most limit names of systems similar to the
+	// unknown name.
+	SuggestNames(name string, limit int) ([]string, error)
+	// SystemsWithin returns all the systems in box between min and max.
+	SystemsWithin(min, max r3.Vec) ([]System, error)
+	// StreamSystemsWithin sends all the systems in box between min and max
+	// to out, out is closed when done.
+	StreamSystemsWithin(min, max r3.Vec, out chan<- System) error
+	// Nearest returns at most k systems selected by filter nearest to
+	// point, the closest first.
+	Nearest(point r3.Vec, k int, filter Filter) ([]System, error)
+	// WithinRadius returns systems selected by filter within radius LY of
+	// point, the closest first.
+	WithinRadius(point r3.Vec, radius float64, filter Filter) ([]System, error)
+}
+
+// System is the part of the system the route search needs.
+type System struct {
+	ID64        uint64
+	X, Y, Z     float64
+	IsNeutron   bool
+	IsScoopable bool
+	// NeutronDistance is distance of the neutron star from arrival in Ls.
+	NeutronDistance float64
+	IsWhiteDwarf    bool
+	// WhiteDwarfDistance is distance of the white dwarf from arrival in Ls.
+	WhiteDwarfDistance float64
+}
+
+// NewSystem returns the search system of full system s.
+func NewSystem(s dump.System) System {
+	return System{
+		ID64:        s.ID64,
+		X:           s.Coordinates.X,
+		Y:           s.Coordinates.Y,
+		Z:           s.Coordinates.Z,
+		IsNeutron:   NeutronInRange(s.Bodies),
+		IsScoopable: ScoopableInRange(s.Bodies),
+
+		NeutronDistance:    NeutronDistance(s.Bodies),
+		IsWhiteDwarf:       WhiteDwarfInRange(s.Bodies),
+		WhiteDwarfDistance: WhiteDwarfDistance(s.Bodies),
+	}
+}
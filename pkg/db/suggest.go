@@ -0,0 +1,188 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SuggestionsLimit is how many names are suggested for unknown name.
+const SuggestionsLimit = 5
+
+// NotFoundError is returned for unknown system name, with names of
+// similar systems the user may have meant.
+type NotFoundError struct {
+	Name        string
+	Suggestions []string
+}
+
+func (e *NotFoundError) Error() string {
+	msg := fmt.Sprintf("unable to find system: %s", e.Name)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(", did you mean: %s?", strings.Join(e.Suggestions, ", "))
+	}
+	return msg
+}
+
+// Is makes errors.Is(err, ErrNotFound) true for NotFoundError.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// NotFound returns NotFoundError for name with suggestions from store.
+// Suggestions are best effort, when they fail the error has none.
+func NotFound(store SystemStore, name string) error {
+	suggestions, _ := store.SuggestNames(name, SuggestionsLimit)
+	return &NotFoundError{Name: name, Suggestions: suggestions}
+}
+
+// suggestWindow is how many names sorted before and after the unknown
+// name are considered at most, to not go through the whole galaxy.
+const suggestWindow = 50000
+
+// SuggestNames returns at most limit names similar to the unknown name,
+// stores use it to suggest the same names. scan calls add with names in
+// sorted order from where the upper case name would be, after it when
+// forward or before it in reverse order otherwise, until add returns
+// false.
+func SuggestNames(name string, limit int, scan func(from string, forward bool, add func(candidate string) bool) error) ([]string, error) {
+	from := strings.ToUpper(name)
+	if from == "" {
+		return nil, nil
+	}
+
+	suggestions := NewSuggestions(name, limit)
+	for _, forward := range []bool{true, false} {
+		scanned := 0
+		err := scan(from, forward, func(candidate string) bool {
+			suggestions.Add(candidate)
+			scanned++
+			return scanned < suggestWindow
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return suggestions.Names(), nil
+}
+
+// Suggestions collects names similar to the unknown name: names starting
+// with it, then names within small edit distance of it, case insensitive.
+type Suggestions struct {
+	name        []rune
+	limit       int
+	maxDistance int
+
+	prefixed []string
+	similar  []suggestion
+}
+
+type suggestion struct {
+	name     string
+	distance int
+}
+
+// NewSuggestions returns empty suggestions for name, limit is how many
+// names Names returns at most.
+func NewSuggestions(name string, limit int) *Suggestions {
+	upper := []rune(strings.ToUpper(name))
+	return &Suggestions{
+		name:  upper,
+		limit: limit,
+		// Allow about one typo per 4 letters.
+		maxDistance: 1 + len(upper)/4,
+	}
+}
+
+// Add considers candidate for suggestion.
+func (s *Suggestions) Add(candidate string) {
+	upper := strings.ToUpper(candidate)
+	if upper == string(s.name) {
+		return
+	}
+	if strings.HasPrefix(upper, string(s.name)) {
+		s.prefixed = append(s.prefixed, candidate)
+		if len(s.prefixed) > 2*s.limit {
+			sort.Strings(s.prefixed)
+			s.prefixed = s.prefixed[:s.limit]
+		}
+		return
+	}
+
+	c := []rune(upper)
+	if abs(len(c)-len(s.name)) > s.maxDistance {
+		return
+	}
+	d := editDistance(s.name, c)
+	if d > s.maxDistance {
+		return
+	}
+	s.similar = append(s.similar, suggestion{name: candidate, distance: d})
+	if len(s.similar) > 2*s.limit {
+		s.sortSimilar()
+		s.similar = s.similar[:s.limit]
+	}
+}
+
+func (s *Suggestions) sortSimilar() {
+	sort.Slice(s.similar, func(i, j int) bool {
+		if s.similar[i].distance != s.similar[j].distance {
+			return s.similar[i].distance < s.similar[j].distance
+		}
+		return s.similar[i].name < s.similar[j].name
+	})
+}
+
+// Names returns the suggested names, names starting with the unknown
+// name first, then the most similar ones.
+func (s *Suggestions) Names() []string {
+	sort.Strings(s.prefixed)
+	s.sortSimilar()
+
+	names := append([]string{}, s.prefixed...)
+	for _, similar := range s.similar {
+		names = append(names, similar.name)
+	}
+	if len(names) > s.limit {
+		names = names[:s.limit]
+	}
+	return names
+}
+
+// editDistance returns Levenshtein distance of a and b.
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range a {
+		cur[0] = i + 1
+		for j := range b {
+			cost := 1
+			if a[i] == b[j] {
+				cost = 0
+			}
+			cur[j+1] = minInt(prev[j+1]+1, cur[j]+1, prev[j]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance([]rune("SOL"), []rune("SOL")))
+	assert.Equal(t, 1, editDistance([]rune("SOOL"), []rune("SOL")))
+	assert.Equal(t, 1, editDistance([]rune("SOL"), []rune("SAL")))
+	assert.Equal(t, 2, editDistance([]rune("COLONIA"), []rune("CLOONIA")))
+	assert.Equal(t, 3, editDistance([]rune(""), []rune("SOL")))
+}
+
+func TestSuggestions(t *testing.T) {
+	s := NewSuggestions("sool", 3)
+	for _, name := range []string{"Sol", "Sool", "Soolati", "Sooma", "Colonia", "Sosl", "Sooly"} {
+		s.Add(name)
+	}
+	// Starting with the name first, then by edit distance.
+	assert.Equal(t, []string{"Soolati", "Sooly", "Sol"}, s.Names())
+
+	assert.Empty(t, NewSuggestions("Sol", 3).Names())
+}
+
+func TestSuggestNames(t *testing.T) {
+	sorted := []string{"ARRAKIS", "SOL", "SOOLATI", "SOSL", "ZOOL"}
+	scan := func(from string, forward bool, add func(string) bool) error {
+		i := sort.SearchStrings(sorted, from)
+		if forward {
+			for ; i < len(sorted) && add(sorted[i]); i++ {
+			}
+			return nil
+		}
+		for i--; i >= 0 && add(sorted[i]); i-- {
+		}
+		return nil
+	}
+	names, err := SuggestNames("sool", 3, scan)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SOOLATI", "SOL", "SOSL"}, names)
+
+	// Typo in the first letters still finds names sorted nearby.
+	names, err = SuggestNames("xool", 3, scan)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ZOOL", "SOL", "SOSL"}, names)
+
+	// Scan stops after suggestWindow names on each side.
+	scanned := map[bool]int{}
+	_, err = SuggestNames("s", 3, func(from string, forward bool, add func(string) bool) error {
+		assert.Equal(t, "S", from)
+		for add("SOL") {
+			scanned[forward]++
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[bool]int{true: suggestWindow - 1, false: suggestWindow - 1}, scanned)
+
+	names, err = SuggestNames("", 3, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+}
+
+func TestNotFoundError(t *testing.T) {
+	var err error = &NotFoundError{Name: "Sool", Suggestions: []string{"Sol", "Sosl"}}
+	assert.True(t, errors.Is(errors.Wrap(err, "invalid FROM system"), ErrNotFound))
+	assert.Equal(t, "unable to find system: Sool, did you mean: Sol, Sosl?", err.Error())
+	assert.Equal(t, "unable to find system: Sool", (&NotFoundError{Name: "Sool"}).Error())
+}
@@ -26,13 +26,13 @@ func TestDistance(t *testing.T) {
 var Out bool
 
 func BenchmarkDistance(b *testing.B) {
-	assert.True(b, Distance(r3.Vec{0, 0, 0}, r3.Vec{0, 0, 0}) < 1)
-	assert.False(b, Distance(r3.Vec{1, 1, 1}, r3.Vec{0, 0, 0}) < 1)
+	assert.True(b, Distance(r3.Vec{X: 0, Y: 0, Z: 0}, r3.Vec{X: 0, Y: 0, Z: 0}) < 1)
+	assert.False(b, Distance(r3.Vec{X: 1, Y: 1, Z: 1}, r3.Vec{X: 0, Y: 0, Z: 0}) < 1)
 
 	// 9759423	       124 ns/op	       0 B/op	       0 allocs/op
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		Out = Distance(r3.Vec{0, 0, 0}, r3.Vec{0, 0, 0}) < 1
-		Out = Distance(r3.Vec{1, 1, 1}, r3.Vec{0, 0, 0}) < 1
+		Out = Distance(r3.Vec{X: 0, Y: 0, Z: 0}, r3.Vec{X: 0, Y: 0, Z: 0}) < 1
+		Out = Distance(r3.Vec{X: 1, Y: 1, Z: 1}, r3.Vec{X: 0, Y: 0, Z: 0}) < 1
 	}
 }
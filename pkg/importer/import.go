@@ -7,6 +7,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/lunemec/ed-router/pkg/config"
 	"github.com/lunemec/ed-router/pkg/db/boltdb"
 	"github.com/lunemec/ed-router/pkg/models/dump"
 
@@ -17,11 +18,6 @@ import (
 	"github.com/vbauerster/mpb/v5/decor"
 )
 
-var (
-	IndexDB  = "index_xyz.db"
-	GalaxyDB = "galaxy.db"
-)
-
 // Import is the main entrypoint for path routing.Import
 // expects 2 arguments [from] and [to].
 func Import(cmd *cobra.Command, args []string) error {
@@ -36,7 +32,11 @@ func Import(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
-	db, err := boltdb.Open(IndexDB, GalaxyDB, false)
+	cfg, err := config.FromCommand(cmd)
+	if err != nil {
+		return err
+	}
+	db, err := boltdb.Open(cfg.IndexDBPath(), cfg.GalaxyDBPath(), false)
 	if err != nil {
 		return errors.Wrap(err, "unable to open DB")
 	}
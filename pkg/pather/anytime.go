@@ -0,0 +1,72 @@
+package pather
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WithEpsilon runs weighted A*, which weights the estimate of remaining
+// cost by epsilon. Epsilon above 1 finds the path faster, at most epsilon
+// times more expensive than the cheapest one when the estimate is
+// admissible.
+func WithEpsilon(epsilon float64) Option {
+	return func(p *pather) {
+		p.epsilon = epsilon
+	}
+}
+
+// Improvement is better path found by the anytime search.
+type Improvement struct {
+	Path []Waypoint
+	Cost float64
+	// Epsilon is the weight of the search which found the path.
+	Epsilon float64
+}
+
+// nextEpsilon halves the weight above 1, small enough weight is 1.
+func nextEpsilon(epsilon float64) float64 {
+	next := 1 + (epsilon-1)/2
+	if next < 1.01 {
+		return 1
+	}
+	return next
+}
+
+// PathAnytime finds the first path with the epsilon set by WithEpsilon,
+// then keeps searching with smaller epsilon for cheaper paths for improve
+// time or until epsilon is 1 or ctx is done, calling improved with every
+// cheaper path. It returns the cheapest path found, error only when there
+// is none.
+func (p *pather) PathAnytime(ctx context.Context, improve time.Duration, improved func(Improvement)) ([]Waypoint, float64, error) {
+	path, cost, err := p.PathContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	improved(Improvement{Path: path, Cost: cost, Epsilon: p.epsilon})
+
+	// Time to improve the path runs once there is one.
+	ctx, cancel := context.WithTimeout(ctx, improve)
+	defer cancel()
+	p.ctx = ctx
+	defer func() {
+		p.bound = 0
+	}()
+	for p.epsilon > 1 && ctx.Err() == nil {
+		p.epsilon = nextEpsilon(p.epsilon)
+		p.bound = cost
+		nodes, c, err := p.astar()
+		if errors.Cause(err) == ErrNoPath {
+			// There is no cheaper path with this epsilon.
+			continue
+		}
+		if err != nil {
+			// Cancelled, we still have the path.
+			break
+		}
+		path, cost = p.waypoints(nodes), c
+		improved(Improvement{Path: path, Cost: cost, Epsilon: p.epsilon})
+	}
+	return path, cost, nil
+}
@@ -0,0 +1,78 @@
+package pather
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextEpsilon(t *testing.T) {
+	assert.EqualValues(t, 2, nextEpsilon(3))
+	assert.EqualValues(t, 1.5, nextEpsilon(2))
+	assert.EqualValues(t, 1.015625, nextEpsilon(1.03125))
+	assert.EqualValues(t, 1, nextEpsilon(1.015625))
+	assert.EqualValues(t, 1, nextEpsilon(1))
+}
+
+func TestPathEpsilon(t *testing.T) {
+	systems := testGalaxy(500, 800)
+
+	exact := testPather(bigTankShip(), systems)
+	_, exactCost, found := exact.Path()
+	assert.True(t, found)
+
+	weighted := testPather(bigTankShip(), systems, WithEpsilon(3))
+	_, cost, found := weighted.Path()
+	assert.True(t, found)
+	assert.True(t, weighted.expanded <= exact.expanded)
+	assert.True(t, cost >= exactCost)
+}
+
+func TestPathAnytime(t *testing.T) {
+	systems := testGalaxy(300, 600)
+	p := testPather(bigTankShip(), systems, WithEpsilon(5))
+
+	var improvements []Improvement
+	path, cost, err := p.PathAnytime(context.Background(), time.Minute, func(i Improvement) {
+		improvements = append(improvements, i)
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, improvements)
+	assert.EqualValues(t, 5, improvements[0].Epsilon)
+	for i := 1; i < len(improvements); i++ {
+		assert.True(t, improvements[i].Cost < improvements[i-1].Cost)
+		assert.True(t, improvements[i].Epsilon < improvements[i-1].Epsilon)
+	}
+	last := improvements[len(improvements)-1]
+	assert.Equal(t, last.Path, path)
+	assert.Equal(t, last.Cost, cost)
+	assert.EqualValues(t, 1, p.epsilon)
+	assert.Zero(t, p.bound)
+}
+
+func TestPathAnytimeCancelled(t *testing.T) {
+	p := testPather(bigTankShip(), testGalaxy(100, 500), WithEpsilon(2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := p.PathAnytime(ctx, time.Minute, func(Improvement) {
+		t.Error("no path expected")
+	})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestPathAnytimeNoTimeToImprove(t *testing.T) {
+	p := testPather(bigTankShip(), testGalaxy(100, 500), WithEpsilon(2))
+
+	// The first path is found whatever the time to improve it.
+	var improvements []Improvement
+	path, _, err := p.PathAnytime(context.Background(), 0, func(i Improvement) {
+		improvements = append(improvements, i)
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, path)
+	assert.Len(t, improvements, 1)
+	assert.EqualValues(t, 2, p.epsilon)
+}
@@ -0,0 +1,143 @@
+package pather
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// ErrBudgetExceeded is returned when the search expands more nodes than
+// allowed by WithBudget.
+var ErrBudgetExceeded = errors.New("search budget exceeded")
+
+// WithBudget limits number of nodes the search may expand in all the
+// corridors, 0 means no limit.
+func WithBudget(nodes int) Option {
+	return func(p *pather) {
+		p.budget = nodes
+	}
+}
+
+// openItem is node in the open set with its cost so far g and estimated
+// total cost f.
+type openItem struct {
+	id   int
+	f, g float64
+}
+
+// openSet is binary heap of nodes to expand, the lowest f first. Ties are
+// broken by higher g, which is closer to the goal, then by lower node id,
+// so the search is deterministic.
+type openSet []openItem
+
+func (o openSet) Len() int { return len(o) }
+
+func (o openSet) Less(i, j int) bool {
+	if o[i].f != o[j].f {
+		return o[i].f < o[j].f
+	}
+	if o[i].g != o[j].g {
+		return o[i].g > o[j].g
+	}
+	return o[i].id < o[j].id
+}
+
+func (o openSet) Swap(i, j int) { o[i], o[j] = o[j], o[i] }
+
+func (o *openSet) Push(x interface{}) { *o = append(*o, x.(openItem)) }
+
+func (o *openSet) Pop() interface{} {
+	old := *o
+	item := old[len(old)-1]
+	*o = old[:len(old)-1]
+	return item
+}
+
+// search is state of single A* search, indexed by node id.
+type search struct {
+	open   openSet
+	g      []float64
+	parent []int
+	closed []bool
+}
+
+// grow makes room for state of nodes created since the last call.
+func (s *search) grow(nodes int) {
+	for len(s.g) < nodes {
+		s.g = append(s.g, math.Inf(1))
+		s.parent = append(s.parent, -1)
+		s.closed = append(s.closed, false)
+	}
+}
+
+// astar searches for the cheapest path from p.start to p.to, weighted by
+// p.epsilon. It returns nodes of the path from start to the goal node and
+// the path cost, ErrNoPath when there is none cheaper than p.bound.
+func (p *pather) astar() ([]*node, float64, error) {
+	var s search
+	s.grow(len(p.byID))
+	s.g[p.start.id] = 0
+	heap.Push(&s.open, openItem{id: p.start.id, f: p.epsilon * p.start.estimate()})
+
+	for s.open.Len() > 0 {
+		item := heap.Pop(&s.open).(openItem)
+		// Nodes are pushed again instead of updating them in the heap,
+		// the stale items are skipped.
+		if s.closed[item.id] || item.g > s.g[item.id] {
+			continue
+		}
+		n := p.byID[item.id]
+		// Any node in To is the goal, we do not care how much fuel is left
+		// once we arrive.
+		if n.system.ID64 == p.to.ID64 {
+			var path []*node
+			for _, id := range s.path(n.id) {
+				path = append(path, p.byID[id])
+			}
+			return path, item.g, nil
+		}
+		s.closed[item.id] = true
+
+		err := p.expand(n.system)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, neighbor := range n.neighbors() {
+			s.grow(len(p.byID))
+			if s.closed[neighbor.id] {
+				continue
+			}
+			g := item.g + n.cost(neighbor)
+			h := neighbor.estimate()
+			// There is no path to the goal from the neighbor.
+			if math.IsInf(h, 1) {
+				continue
+			}
+			// Paths which can not be cheaper than the bound are not
+			// interesting, the estimate is never too high.
+			if p.bound > 0 && g+h >= p.bound {
+				continue
+			}
+			if g < s.g[neighbor.id] {
+				s.g[neighbor.id] = g
+				s.parent[neighbor.id] = n.id
+				heap.Push(&s.open, openItem{id: neighbor.id, f: g + p.epsilon*h, g: g})
+			}
+		}
+	}
+	return nil, 0, ErrNoPath
+}
+
+// path returns ids from the start to id.
+func (s *search) path(id int) []int {
+	var path []int
+	for ; id != -1; id = s.parent[id] {
+		path = append(path, id)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
@@ -0,0 +1,175 @@
+package pather
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/lunemec/ed-router/pkg/ship"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestOpenSetOrder(t *testing.T) {
+	var open openSet
+	heap.Push(&open, openItem{id: 3, f: 10, g: 2})
+	heap.Push(&open, openItem{id: 1, f: 5, g: 1})
+	heap.Push(&open, openItem{id: 2, f: 10, g: 8})
+	heap.Push(&open, openItem{id: 0, f: 10, g: 2})
+
+	var ids []int
+	for open.Len() > 0 {
+		ids = append(ids, heap.Pop(&open).(openItem).id)
+	}
+	// Lowest f, then highest g, then lowest id.
+	assert.Equal(t, []int{1, 2, 0, 3}, ids)
+}
+
+func TestAstarNodeIDs(t *testing.T) {
+	p := testPather(smallTankShip(), testLine(5, 20))
+
+	_, _, err := p.PathContext(context.Background())
+	assert.NoError(t, err)
+	for i, n := range p.byID {
+		assert.Equal(t, i, n.id)
+	}
+	assert.Equal(t, 0, p.start.id)
+}
+
+func TestAstarDeterministic(t *testing.T) {
+	// Both middle systems make the same path cost.
+	systems := testLine(3, 60)
+	systems = append(systems[:2], &System{ID64: 4, Coordinates: systems[1].Coordinates}, systems[2])
+
+	first, _, found := testPather(smallTankShip(), systems).Path()
+	assert.True(t, found)
+	for i := 0; i < 10; i++ {
+		path, _, _ := testPather(smallTankShip(), systems).Path()
+		assert.Equal(t, pathSystems(first), pathSystems(path))
+	}
+}
+
+func TestAstarBudget(t *testing.T) {
+	systems := testLine(20, 16)
+	for _, s := range systems {
+		s.Scoopable = true
+	}
+
+	p := testPather(smallTankShip(), systems, WithBudget(3))
+
+	_, _, err := p.PathContext(context.Background())
+	assert.Equal(t, ErrBudgetExceeded, err)
+	assert.Equal(t, 3, p.expanded)
+
+	p = testPather(smallTankShip(), systems, WithBudget(1000))
+	_, _, err = p.PathContext(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestEstimateAdmissible(t *testing.T) {
+	for name, c := range CostFuncs {
+		for seed := int64(1); seed <= 3; seed++ {
+			systems := testGalaxySeed(seed, 150, 400)
+			opts := []Option{WithCostFunc(c), WithWhiteDwarf()}
+
+			// Without the estimate A* is Dijkstra's algorithm.
+			dijkstra := testPather(bigTankShip(), systems, append(opts, WithEpsilon(0))...)
+			_, want, err := dijkstra.astar()
+			assert.NoError(t, err, name)
+
+			p := testPather(bigTankShip(), systems, opts...)
+			nodes, cost, err := p.astar()
+			assert.NoError(t, err, name)
+			assert.InDelta(t, want, cost, 1e-6, name)
+
+			// Rest of the cheapest path is the cheapest path from its
+			// node, which the estimate must not exceed.
+			remaining := cost
+			for i, n := range nodes {
+				assert.True(t, n.estimate() <= remaining+1e-9, name)
+				if i+1 < len(nodes) {
+					remaining -= n.cost(nodes[i+1])
+				}
+			}
+			assert.InDelta(t, 0, remaining, 1e-6, name)
+		}
+	}
+}
+
+func TestEstimateConsistent(t *testing.T) {
+	systems := testGalaxy(150, 400)
+	for name, c := range CostFuncs {
+		p := testPather(bigTankShip(), systems, WithCostFunc(c))
+		for _, s := range systems {
+			n := p.node(s, p.ship, false, false, p.injections)
+			for _, neighbor := range n.neighbors() {
+				assert.True(t, n.estimate() <= n.cost(neighbor)+neighbor.estimate()+1e-9, name)
+			}
+		}
+	}
+}
+
+func TestEstimateCountsJumps(t *testing.T) {
+	systems := []*System{
+		{ID64: 1, Coordinates: r3.Vec{X: 0}},
+		{ID64: 2, Coordinates: r3.Vec{X: 60}},
+		// Too far from all the others.
+		{ID64: 3, Coordinates: r3.Vec{Y: 1000}},
+		{ID64: 4, Coordinates: r3.Vec{X: 120}, Neutron: true},
+		{ID64: 5, Coordinates: r3.Vec{X: 360}},
+	}
+	p := testPather(smallTankShip(), systems, WithCostFunc(FewestJumps))
+	assert.Equal(t, map[uint64]int{1: 3, 2: 2, 4: 1, 5: 0}, p.jumps)
+	assert.EqualValues(t, 3, p.estimate(systems[0]))
+	assert.True(t, math.IsInf(p.estimate(systems[2]), 1))
+
+	// Injection makes the jump from the start to the neutron star.
+	p = testPather(smallTankShip(), systems, WithCostFunc(FewestJumps), WithInjections(Injections{Premium: 1}))
+	assert.EqualValues(t, 2, p.estimate(systems[0]))
+}
+
+// testGalaxy returns count random systems in a slab length LY long, the
+// first at the start and the last at the end of it.
+func testGalaxy(count int, length float64) []*System {
+	return testGalaxySeed(1, count, length)
+}
+
+func testGalaxySeed(seed int64, count int, length float64) []*System {
+	rnd := rand.New(rand.NewSource(seed))
+	systems := make([]*System, count)
+	for i := range systems {
+		systems[i] = &System{
+			ID64: uint64(i + 1),
+			Coordinates: r3.Vec{
+				X: rnd.Float64() * length,
+				Y: rnd.Float64()*200 - 100,
+				Z: rnd.Float64()*40 - 20,
+			},
+			Scoopable:  rnd.Float64() < 0.5,
+			Neutron:    rnd.Float64() < 0.02,
+			WhiteDwarf: rnd.Float64() < 0.05,
+		}
+	}
+	systems[0].Coordinates = r3.Vec{}
+	systems[count-1].Coordinates = r3.Vec{X: length}
+	return systems
+}
+
+// bigTankShip has range of ~67 LY and fuel for many jumps.
+func bigTankShip() ship.Ship {
+	return ship.New(32, 346.9, 1692.6, 5, 10.5, 878, ship.FSDRating["A"], ship.FSDClass[5])
+}
+
+func BenchmarkPath(b *testing.B) {
+	systems := testGalaxy(3000, 2000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, found := testPather(bigTankShip(), systems).Path()
+		if !found {
+			b.Fatal("path not found")
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package pather
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/lunemec/ed-router/pkg/db"
+
+	"github.com/pkg/errors"
+)
+
+// PermitLocked are systems which can not be entered without a permit,
+// routes avoid them unless the permit is given to ResolveAvoid.
+var PermitLocked = []string{
+	"Achenar",
+	"Alioth",
+	"Beta Hydri",
+	"CD-43 11917",
+	"Hors",
+	"Isinor",
+	"LFT 509",
+	"Luyten 347-14",
+	"Mbooni",
+	"Shinrarta Dezhra",
+	"Sirius",
+	"Sol",
+	"Summerland",
+	"Tiliala",
+	"Van Maanen's Star",
+	"Vega",
+}
+
+// PermitLockedSectors are sectors which can not be entered without
+// a permit, routes avoid all the systems in them unless the permit is given
+// to ResolveAvoid.
+var PermitLockedSectors = []string{
+	"Bleia1",
+	"Bleia2",
+	"Bleia3",
+	"Bleia4",
+	"Bleia5",
+	"Bovomit",
+	"Dryman",
+	"Froadik",
+	"Hyponia",
+	"Praei1",
+	"Praei2",
+	"Praei3",
+	"Praei4",
+	"Praei5",
+	"Praei6",
+	"Sidgoir",
+}
+
+// SectorPrefix marks avoided name as sector, which excludes all the
+// systems in it.
+const SectorPrefix = "sector:"
+
+// Avoid are ID64s of systems routes must not go through, with why each of
+// them is avoided.
+type Avoid map[uint64]string
+
+// ResolveAvoid finds systems routes must avoid once for all the routes
+// using them. Each of avoid is system ID64, system name or sector name
+// after SectorPrefix. PermitLocked systems and PermitLockedSectors are
+// avoided unless there is permit for them, permit "all" disables both of
+// the lists.
+func ResolveAvoid(store db.SystemStore, avoid, permits []string) (Avoid, error) {
+	out := make(Avoid)
+	for _, name := range avoid {
+		ids, err := systemIDs(store, name)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return nil, errors.Errorf("unable to find system or sector to avoid: %s", name)
+		}
+		out.add(ids, "is avoided by: "+name)
+	}
+
+	permitted := make(map[string]bool)
+	for _, permit := range permits {
+		permitted[strings.ToUpper(permit)] = true
+	}
+	if permitted["ALL"] {
+		return out, nil
+	}
+	locked := []struct {
+		names     []string
+		systemIDs func(store db.SystemStore, name string) ([]uint64, error)
+	}{
+		{PermitLocked, namedSystemIDs},
+		{PermitLockedSectors, sectorSystemIDs},
+	}
+	for _, l := range locked {
+		for _, name := range l.names {
+			if permitted[strings.ToUpper(name)] {
+				continue
+			}
+			// Databases of only part of the galaxy do not have all of them.
+			ids, err := l.systemIDs(store, name)
+			if err != nil {
+				return nil, err
+			}
+			out.add(ids, "needs permit for: "+name)
+		}
+	}
+	return out, nil
+}
+
+func (a Avoid) add(ids []uint64, reason string) {
+	for _, id := range ids {
+		if _, ok := a[id]; !ok {
+			a[id] = reason
+		}
+	}
+}
+
+// WithAvoid excludes systems resolved by ResolveAvoid from the route.
+func WithAvoid(avoid Avoid) Option {
+	return func(p *pather) {
+		p.avoid = avoid
+	}
+}
+
+// systemIDs returns ID64s of system with ID64 or exactly the name, or of
+// all the systems in sector of the name after SectorPrefix.
+func systemIDs(store db.SystemStore, name string) ([]uint64, error) {
+	if strings.HasPrefix(strings.ToLower(name), SectorPrefix) {
+		return sectorSystemIDs(store, strings.TrimSpace(name[len(SectorPrefix):]))
+	}
+	id64, err := strconv.ParseUint(name, 10, 64)
+	if err == nil {
+		return []uint64{id64}, nil
+	}
+	return namedSystemIDs(store, name)
+}
+
+// namedSystemIDs returns ID64s of all the systems sharing exactly the name.
+func namedSystemIDs(store db.SystemStore, name string) ([]uint64, error) {
+	ids, err := store.SystemIDsByName(name)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// sectorSystemIDs returns ID64s of all the systems in sector of the name.
+func sectorSystemIDs(store db.SystemStore, name string) ([]uint64, error) {
+	// Systems in the sector are named "<sector> AB-C d1-23".
+	return store.SystemIDsByPrefix(name + " ")
+}
+
+// avoided is true when the route must not go through system s.
+func (p *pather) avoided(s *System) bool {
+	_, ok := p.avoid[s.ID64]
+	return ok || p.inVolume(s)
+}
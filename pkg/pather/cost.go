@@ -0,0 +1,186 @@
+package pather
+
+import (
+	"math"
+	"sort"
+
+	"github.com/lunemec/ed-router/pkg/ship"
+)
+
+const (
+	// secondsToJump is time it takes to charge the FSD, jump and get
+	// through hyperspace.
+	secondsToJump float64 = 45
+	// secondsToSupercharge is time spent inside the neutron star jet cone.
+	secondsToSupercharge float64 = 10
+	// unscoopablePenalty is added to cost of jumps to unscoopable systems
+	// by the Safest strategy. It is equivalent of that many jumps.
+	unscoopablePenalty float64 = 100
+	// whiteDwarfRisk is added to cost of jumps supercharged by white dwarf
+	// for the heat and module damage it causes. It is equivalent of that
+	// many jumps.
+	whiteDwarfRisk float64 = 1
+
+	// neutronBoost is jump range multiplier of neutron star supercharge.
+	neutronBoost float64 = 4
+	// whiteDwarfBoost is jump range multiplier of white dwarf supercharge.
+	whiteDwarfBoost float64 = 1.5
+)
+
+// Jump is a single jump the search considers.
+type Jump struct {
+	From     *System
+	To       *System
+	Distance float64 // in LY
+	Boost    float64 // jump range multiplier, 4 for neutron supercharge, or of FSD injection
+	Ship     ship.Ship
+	// Arrival is the ship state when arriving to To, before refueling.
+	Arrival ship.Ship
+	// Refuel is true when the ship scoops in To, in the destination only
+	// when the route goes on from there.
+	Refuel bool
+	// Last is true when To is the destination.
+	Last bool
+	// Supercharge is true when the jump is supercharged by neutron star or
+	// white dwarf in From.
+	Supercharge bool
+	// WhiteDwarf is true when the jump is supercharged by white dwarf in From.
+	WhiteDwarf bool
+	// Injection is FSD injection used for the jump.
+	Injection Injection
+}
+
+// FuelUsed returns how much fuel in tons the jump consumes.
+func (j Jump) FuelUsed() float64 {
+	return j.Ship.FuelRemaining() - j.Arrival.FuelRemaining()
+}
+
+// CostFunc calculates cost of a single jump, which is what the search
+// minimizes.
+type CostFunc interface {
+	// Cost returns cost of the jump, must not be negative.
+	Cost(j Jump) float64
+	// MinCost returns the lowest cost any jump can have, the search
+	// estimates remaining cost from it.
+	MinCost() float64
+}
+
+type (
+	fewestJumps  struct{}
+	shortestTime struct{}
+	leastFuel    struct{}
+	safest       struct{}
+)
+
+var (
+	// FewestJumps minimizes number of jumps.
+	FewestJumps CostFunc = fewestJumps{}
+	// ShortestTime minimizes time in seconds it takes to get to the destination.
+	ShortestTime CostFunc = shortestTime{}
+	// LeastFuel minimizes fuel consumed in tons.
+	LeastFuel CostFunc = leastFuel{}
+	// Safest minimizes number of jumps strongly preferring scoopable systems.
+	Safest CostFunc = safest{}
+
+	// CostFuncs maps strategy names to cost functions.
+	CostFuncs = map[string]CostFunc{
+		"jumps": FewestJumps,
+		"time":  ShortestTime,
+		"fuel":  LeastFuel,
+		"safe":  Safest,
+	}
+)
+
+// CostFuncNames returns sorted names of available cost functions.
+func CostFuncNames() []string {
+	var names []string
+	for name := range CostFuncs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (fewestJumps) Cost(j Jump) float64 {
+	return 1 + boostPenalty(j)
+}
+
+func (fewestJumps) MinCost() float64 {
+	return 1
+}
+
+func (shortestTime) Cost(j Jump) float64 {
+	seconds := boostPenalty(j)*secondsToJump + secondsToJump
+	// Supercharge happens before the jump, in From.
+	if j.Supercharge {
+		seconds += secondsToSuperchargeIn(j.From)
+	}
+	if j.Injection != NoInjection {
+		seconds += secondsToSynthesise
+	}
+	if j.Refuel {
+		seconds += j.Arrival.SecondsToScoop()
+	}
+	return seconds
+}
+
+// MinCost of ShortestTime is time of the jump itself, without any
+// supercharging or scooping.
+func (shortestTime) MinCost() float64 {
+	return secondsToJump
+}
+
+// Cost of LeastFuel is only the fuel used, white dwarf supercharge and
+// FSD injections save fuel and their risk or materials are not considered.
+func (leastFuel) Cost(j Jump) float64 {
+	return j.FuelUsed()
+}
+
+// MinCost of LeastFuel is 0, because fuel used falls much faster than
+// the jump distance, so many short jumps may use almost no fuel.
+func (leastFuel) MinCost() float64 {
+	return 0
+}
+
+func (safest) Cost(j Jump) float64 {
+	cost := 1 + boostPenalty(j)
+	if j.Last || j.To.Scoopable {
+		return cost
+	}
+	return cost + unscoopablePenalty
+}
+
+func (safest) MinCost() float64 {
+	return 1
+}
+
+// boostPenalty returns whiteDwarfRisk for jumps supercharged by white
+// dwarf plus injectionCost of FSD injection used, in number of jumps.
+func boostPenalty(j Jump) float64 {
+	var penalty float64
+	if j.WhiteDwarf {
+		penalty += whiteDwarfRisk
+	}
+	return penalty + injectionCost[j.Injection]
+}
+
+// secondsInSupercruise approximates how long it takes to travel distance
+// in Ls in supercruise. The curve is fitted to ~40s for 100 Ls, ~90s for
+// 1000 Ls and ~200s for 10000 Ls, since acceleration depends on gravity
+// wells which we do not know.
+func secondsInSupercruise(ls float64) float64 {
+	if ls <= 0 {
+		return 0
+	}
+	return 7.9 * math.Pow(ls, 0.352)
+}
+
+// secondsToSuperchargeIn is how long it takes to fly to the neutron star
+// or white dwarf in system s and supercharge there.
+func secondsToSuperchargeIn(s *System) float64 {
+	ls := s.WhiteDwarfDistance
+	if s.Neutron {
+		ls = s.NeutronDistance
+	}
+	return secondsInSupercruise(ls) + secondsToSupercharge
+}
@@ -0,0 +1,124 @@
+package pather
+
+import (
+	"testing"
+
+	"github.com/lunemec/ed-router/pkg/ship"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestSecondsInSupercruise(t *testing.T) {
+	assert.EqualValues(t, 0, secondsInSupercruise(0))
+	assert.InDelta(t, 40, secondsInSupercruise(100), 1)
+	assert.InDelta(t, 90, secondsInSupercruise(1000), 1)
+	assert.InDelta(t, 200, secondsInSupercruise(10000), 5)
+}
+
+func TestSecondsToSuperchargeIn(t *testing.T) {
+	neutron := &System{Neutron: true, NeutronDistance: 100}
+	whiteDwarf := &System{WhiteDwarf: true, WhiteDwarfDistance: 1000}
+
+	assert.EqualValues(t, secondsToSupercharge+secondsInSupercruise(100), secondsToSuperchargeIn(neutron))
+	assert.EqualValues(t, secondsToSupercharge+secondsInSupercruise(1000), secondsToSuperchargeIn(whiteDwarf))
+}
+
+func TestCostFuncs(t *testing.T) {
+	s := smallTankShip()
+	arrival, err := s.Jump(50)
+	assert.NoError(t, err)
+
+	j := Jump{
+		From:    &System{},
+		To:      &System{},
+		Boost:   1,
+		Ship:    s,
+		Arrival: arrival,
+	}
+	assert.EqualValues(t, 1, FewestJumps.Cost(j))
+	assert.EqualValues(t, secondsToJump, ShortestTime.Cost(j))
+	assert.EqualValues(t, s.FuelRemaining()-arrival.FuelRemaining(), LeastFuel.Cost(j))
+	assert.EqualValues(t, 1+unscoopablePenalty, Safest.Cost(j))
+
+	j.Refuel = true
+	j.To = &System{Scoopable: true}
+	assert.EqualValues(t, secondsToJump+arrival.SecondsToScoop(), ShortestTime.Cost(j))
+	assert.EqualValues(t, 1, Safest.Cost(j))
+
+	// Scooping in the destination of a leg, the route goes on.
+	j.Last = true
+	assert.EqualValues(t, secondsToJump+arrival.SecondsToScoop(), ShortestTime.Cost(j))
+	assert.EqualValues(t, 1, Safest.Cost(j))
+
+	j.Refuel = false
+	assert.EqualValues(t, secondsToJump, ShortestTime.Cost(j))
+
+	// Arriving to neutron star does not mean supercharging there.
+	j.To = &System{Neutron: true, NeutronDistance: 100}
+	assert.EqualValues(t, secondsToJump, ShortestTime.Cost(j))
+
+	j.Supercharge = true
+	j.From = &System{Neutron: true, NeutronDistance: 100}
+	assert.EqualValues(t, 1, FewestJumps.Cost(j))
+	assert.EqualValues(t, secondsToJump+secondsInSupercruise(100)+secondsToSupercharge, ShortestTime.Cost(j))
+
+	j.WhiteDwarf = true
+	j.From = &System{WhiteDwarf: true, WhiteDwarfDistance: 100}
+	assert.EqualValues(t, 1+whiteDwarfRisk, FewestJumps.Cost(j))
+	assert.EqualValues(t, secondsToJump+secondsInSupercruise(100)+secondsToSupercharge+whiteDwarfRisk*secondsToJump, ShortestTime.Cost(j))
+	assert.EqualValues(t, 1+whiteDwarfRisk, Safest.Cost(j))
+}
+
+func TestCostFuncNames(t *testing.T) {
+	assert.Equal(t, []string{"fuel", "jumps", "safe", "time"}, CostFuncNames())
+}
+
+// TestSafestAvoidsUnscoopable tests 2 jump route through unscoopable system
+// against 3 jump route through scoopable ones.
+func TestSafestAvoidsUnscoopable(t *testing.T) {
+	var (
+		from        = &System{ID64: 1, Coordinates: r3.Vec{X: 0, Y: 0, Z: 0}}
+		unscoopable = &System{ID64: 2, Coordinates: r3.Vec{X: 64, Y: 0, Z: 0}}
+		scoopable1  = &System{ID64: 3, Coordinates: r3.Vec{X: 32, Y: 56, Z: 0}, Scoopable: true}
+		scoopable2  = &System{ID64: 4, Coordinates: r3.Vec{X: 96, Y: 56, Z: 0}, Scoopable: true}
+		to          = &System{ID64: 5, Coordinates: r3.Vec{X: 128, Y: 0, Z: 0}}
+		s           = ship.New(32, 346.9, 1692.6, 5, 10.5, 878, ship.FSDRating["A"], ship.FSDClass[5])
+	)
+
+	p := testPather(s, []*System{from, unscoopable, scoopable1, scoopable2, to})
+	p.costFunc = FewestJumps
+	path, cost, found := p.Path()
+	assert.True(t, found)
+	assert.EqualValues(t, 2, cost)
+	assert.Equal(t, []*System{from, unscoopable, to}, pathSystems(path))
+
+	p = testPather(s, []*System{from, unscoopable, scoopable1, scoopable2, to})
+	p.costFunc = Safest
+	path, cost, found = p.Path()
+	assert.True(t, found)
+	assert.EqualValues(t, 3, cost)
+	assert.Equal(t, []*System{from, scoopable1, scoopable2, to}, pathSystems(path))
+}
+
+func TestMinCost(t *testing.T) {
+	s := smallTankShip()
+	systems := []*System{
+		{},
+		{Scoopable: true},
+		{Neutron: true, NeutronDistance: 1000},
+		{WhiteDwarf: true, WhiteDwarfDistance: 10},
+	}
+	for name, c := range CostFuncs {
+		for _, from := range systems {
+			for _, to := range systems {
+				for _, last := range []bool{false, true} {
+					arrival, err := s.Jump(1)
+					assert.NoError(t, err)
+					j := Jump{From: from, To: to, Distance: 1, Boost: 1, Ship: s, Arrival: arrival, Last: last}
+					assert.True(t, c.Cost(j) >= c.MinCost(), name)
+				}
+			}
+		}
+	}
+}
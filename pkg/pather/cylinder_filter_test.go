@@ -47,3 +47,24 @@ func TestIsNotInCylinder(t *testing.T) {
 	inCylinder := isInCylinder(from, to, 1, point)
 	assert.False(t, inCylinder)
 }
+
+func TestCorridorBounds(t *testing.T) {
+	from := r3.Vec{X: 0, Y: 0, Z: 0}
+	to := r3.Vec{X: 100, Y: 0, Z: 0}
+
+	min, max := corridorBounds(from, to, 20)
+	assert.Equal(t, r3.Vec{X: -20, Y: -20, Z: -20}, min)
+	assert.Equal(t, r3.Vec{X: 120, Y: 20, Z: 20}, max)
+
+	// Systems off the straight line in the corridor must be in the bounds.
+	point := r3.Vec{X: 50, Y: 15, Z: -10}
+	assert.True(t, isInCylinder(from, to, 20, point))
+	assert.True(t, point.Y <= max.Y && point.Z >= min.Z)
+}
+
+func TestCorridor(t *testing.T) {
+	p := testPather(smallTankShip(), testLine(2, 5), WithCorridors(10, 20))
+	assert.InDelta(t, 10*p.ship.JumpRange(), p.Corridor(), 1e-9)
+	p.corridor++
+	assert.InDelta(t, 20*p.ship.JumpRange(), p.Corridor(), 1e-9)
+}
@@ -0,0 +1,119 @@
+package pather
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Injection is grade of synthesised FSD injection (jumponium).
+type Injection int
+
+const (
+	NoInjection Injection = iota
+	BasicInjection
+	StandardInjection
+	PremiumInjection
+)
+
+// secondsToSynthesise is time it takes to synthesise FSD injection.
+const secondsToSynthesise float64 = 10
+
+var (
+	injectionNames = map[Injection]string{
+		NoInjection:       "none",
+		BasicInjection:    "basic",
+		StandardInjection: "standard",
+		PremiumInjection:  "premium",
+	}
+	injectionBoost = map[Injection]float64{
+		NoInjection:       1,
+		BasicInjection:    1.25,
+		StandardInjection: 1.5,
+		PremiumInjection:  2,
+	}
+	// injectionCost is added to cost of jumps using FSD injection for
+	// the materials it consumes. It is equivalent of that many jumps.
+	injectionCost = map[Injection]float64{
+		NoInjection:       0,
+		BasicInjection:    2,
+		StandardInjection: 5,
+		PremiumInjection:  10,
+	}
+	// injectionGrades are in order from the cheapest.
+	injectionGrades = []Injection{BasicInjection, StandardInjection, PremiumInjection}
+)
+
+func (i Injection) String() string {
+	return injectionNames[i]
+}
+
+// Boost is the jump range multiplier of the injection.
+func (i Injection) Boost() float64 {
+	return injectionBoost[i]
+}
+
+// Injections is inventory of FSD injections the route may use.
+type Injections struct {
+	Basic, Standard, Premium int
+}
+
+// ParseInjections creates Injections from grade name -> count map,
+// eg. {"basic": 3, "premium": 1}.
+func ParseInjections(counts map[string]int) (Injections, error) {
+	var inj Injections
+	for name, count := range counts {
+		if count < 0 {
+			return inj, errors.Errorf("number of %s injections must not be negative", name)
+		}
+		switch strings.ToLower(name) {
+		case BasicInjection.String():
+			inj.Basic = count
+		case StandardInjection.String():
+			inj.Standard = count
+		case PremiumInjection.String():
+			inj.Premium = count
+		default:
+			return inj, errors.Errorf("unknown FSD injection grade: %s, use one of: basic, standard, premium", name)
+		}
+	}
+	return inj, nil
+}
+
+// Count returns how many injections of grade are left.
+func (inj Injections) Count(grade Injection) int {
+	switch grade {
+	case BasicInjection:
+		return inj.Basic
+	case StandardInjection:
+		return inj.Standard
+	case PremiumInjection:
+		return inj.Premium
+	}
+	return 0
+}
+
+// Use returns the inventory with one injection of grade used.
+func (inj Injections) Use(grade Injection) Injections {
+	switch grade {
+	case BasicInjection:
+		inj.Basic--
+	case StandardInjection:
+		inj.Standard--
+	case PremiumInjection:
+		inj.Premium--
+	}
+	return inj
+}
+
+// available returns grades with at least one injection left, from the
+// cheapest.
+func (inj Injections) available() []Injection {
+	var grades []Injection
+	for _, grade := range injectionGrades {
+		if inj.Count(grade) > 0 {
+			grades = append(grades, grade)
+		}
+	}
+	return grades
+}
@@ -0,0 +1,21 @@
+package pather
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInjections(t *testing.T) {
+	inj, err := ParseInjections(map[string]int{"basic": 3, "Premium": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, Injections{Basic: 3, Premium: 1}, inj)
+	assert.Equal(t, []Injection{BasicInjection, PremiumInjection}, inj.available())
+	assert.Equal(t, Injections{Basic: 2, Premium: 1}, inj.Use(BasicInjection))
+	assert.Equal(t, inj, inj.Use(NoInjection))
+
+	_, err = ParseInjections(map[string]int{"mythical": 1})
+	assert.Error(t, err)
+	_, err = ParseInjections(map[string]int{"basic": -1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,210 @@
+package pather
+
+import (
+	"fmt"
+
+	"github.com/lunemec/ed-router/pkg/distance"
+	"github.com/lunemec/ed-router/pkg/ship"
+)
+
+// fuelPrecision is the granularity (in tons) in which remaining fuel
+// distinguishes search nodes. Arriving to the same system with fuel
+// differing by less than this is considered the same state.
+const fuelPrecision float64 = 0.5
+
+// node is a single search state: system and the ship state on arrival.
+// This way the remaining fuel is part of the searched graph, and the same
+// system may be visited multiple times with different amount of fuel.
+type node struct {
+	// id is index of the node in pather.byID.
+	id     int
+	system *System
+	ship   ship.Ship
+	// refuel is true when the ship scooped fuel on arrival.
+	refuel bool
+	// supercharged is true when the jump to this node was supercharged.
+	supercharged bool
+	// injections are FSD injections left.
+	injections Injections
+	pather     *pather
+}
+
+type nodeKey struct {
+	id64         uint64
+	fuel         int
+	refuel       bool
+	supercharged bool
+	injections   Injections
+}
+
+// boost is the jump range multiplier when jumping from this node.
+func (n *node) boost() float64 {
+	return n.pather.boost(n.system)
+}
+
+// whiteDwarf is true when jumps from this node are supercharged by
+// white dwarf.
+func (n *node) whiteDwarf() bool {
+	return n.pather.whiteDwarfBoost(n.system)
+}
+
+// jump returns ship state after jumping from this node to system s, with
+// supercharge or the cheapest FSD injection needed to reach it, if any.
+func (n *node) jump(s *System, supercharge bool) (ship.Ship, Injection, error) {
+	var (
+		dist      = distance.Distance(n.system.Coordinates, s.Coordinates)
+		jumpRange = n.ship.JumpRangeWithRemainingFuel()
+		boost     = 1.0
+		injection = NoInjection
+	)
+	switch {
+	case supercharge:
+		boost = n.boost()
+	// FSD injection can not be combined with supercharge.
+	case dist > jumpRange:
+		for _, grade := range n.injections.available() {
+			if dist <= jumpRange*grade.Boost() {
+				injection = grade
+				boost = grade.Boost()
+				break
+			}
+		}
+	}
+	// Boosted jump costs the same fuel as unboosted jump
+	// of the fraction of the distance.
+	arrival, err := n.ship.Jump(dist / boost)
+	return arrival, injection, err
+}
+
+// injectionSystems returns systems reachable only with FSD injections
+// left in the inventory.
+func (n *node) injectionSystems(jumpRange float64) []*System {
+	var (
+		out       []*System
+		prevRange = jumpRange
+	)
+	for _, grade := range n.injections.available() {
+		maxRange := jumpRange * grade.Boost()
+		// Systems just beyond prevRange are reachable only with this
+		// injection, so only the closer ones are filtered out.
+		for _, s := range n.pather.systemsInCube(n.system, maxRange) {
+			// Closer systems are reachable without or with cheaper
+			// injection, even supercharging ones.
+			if distance.Distance(n.system.Coordinates, s.Coordinates) <= prevRange {
+				continue
+			}
+			if n.pather.reaches(n.system, s, maxRange, prevRange) {
+				out = append(out, s)
+			}
+		}
+		prevRange = maxRange
+	}
+	return out
+}
+
+// neighbors returns nodes reachable by single jump from this node.
+func (n *node) neighbors() []*node {
+	var (
+		neighbors []*node
+	)
+
+	jumpRange := n.ship.JumpRangeWithRemainingFuel()
+
+	systemsInRange, err := n.pather.systemsInRangeOf(n.system, jumpRange)
+	if err != nil {
+		fmt.Printf("ERROR: %+v \n", err)
+		return neighbors
+	}
+	systemsInRange = append(systemsInRange, n.injectionSystems(jumpRange)...)
+	for _, otherSystem := range systemsInRange {
+		neighbors = n.appendNeighbors(neighbors, otherSystem, false)
+	}
+
+	// Supercharging takes time and white dwarf damages the ship, so the
+	// systems in range are searched without it too.
+	if n.boost() > 1 {
+		systemsInRange, err = n.pather.systemsInRangeOf(n.system, jumpRange*n.boost())
+		if err != nil {
+			fmt.Printf("ERROR: %+v \n", err)
+			return neighbors
+		}
+		for _, otherSystem := range systemsInRange {
+			neighbors = n.appendNeighbors(neighbors, otherSystem, true)
+		}
+	}
+	return neighbors
+}
+
+// appendNeighbors appends nodes the ship may be in after jump from this
+// node to otherSystem to neighbors.
+func (n *node) appendNeighbors(neighbors []*node, otherSystem *System, supercharge bool) []*node {
+	if n.system.ID64 == otherSystem.ID64 {
+		return neighbors
+	}
+
+	s, injection, err := n.jump(otherSystem, supercharge)
+	if err != nil {
+		n.pather.fuelRejected++
+		return neighbors
+	}
+	// Arriving to a system where we can not scoop with less fuel than
+	// the reserve could leave us stranded.
+	if !otherSystem.Scoopable && s.BelowReserve() {
+		n.pather.fuelRejected++
+		return neighbors
+	}
+	var (
+		injections = n.injections.Use(injection)
+		refueled   = s.Refuel()
+		refuel     = otherSystem.Scoopable && refueled.FuelRemaining() > s.FuelRemaining()
+	)
+	// Once we arrive, we care about fuel only when the route goes on.
+	if otherSystem.ID64 == n.pather.to.ID64 {
+		if refuel && n.pather.destinationRefuel {
+			return append(neighbors, n.pather.node(otherSystem, refueled, true, supercharge, injections))
+		}
+		return append(neighbors, n.pather.node(otherSystem, s, false, supercharge, injections))
+	}
+
+	neighbors = append(neighbors, n.pather.node(otherSystem, s, false, supercharge, injections))
+	// Scooping takes time, so we let the search decide if it is
+	// worth it.
+	if refuel {
+		neighbors = append(neighbors, n.pather.node(otherSystem, refueled, true, supercharge, injections))
+	}
+	return neighbors
+}
+
+// cost is cost of jump to neighbor toNode given by pather's CostFunc.
+func (n *node) cost(toNode *node) float64 {
+	n.pather.systemsChecked += 1
+
+	// Neighbor was already validated in neighbors, so the jump is possible.
+	arrival, injection, _ := n.jump(toNode.system, toNode.supercharged)
+	boost := 1.0
+	switch {
+	case toNode.supercharged:
+		boost = n.boost()
+	case injection != NoInjection:
+		boost = injection.Boost()
+	}
+	return n.pather.costFunc.Cost(Jump{
+		From:     n.system,
+		To:       toNode.system,
+		Distance: distance.Distance(n.system.Coordinates, toNode.system.Coordinates),
+		Boost:    boost,
+		Ship:     n.ship,
+		Arrival:  arrival,
+		Refuel:   toNode.refuel,
+		Last:     toNode.system.ID64 == n.pather.to.ID64,
+
+		Supercharge: toNode.supercharged,
+		WhiteDwarf:  toNode.supercharged && n.whiteDwarf(),
+		Injection:   injection,
+	})
+}
+
+// estimate estimates cost to the goal.
+func (n *node) estimate() float64 {
+	return n.pather.estimate(n.system)
+}
@@ -0,0 +1,314 @@
+package pather
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lunemec/ed-router/pkg/distance"
+	"github.com/lunemec/ed-router/pkg/ship"
+
+	"github.com/dhconnelly/rtreego"
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// testPather creates pather with all the systems loaded, routing from
+// the first system to the last one.
+func testPather(s ship.Ship, systems []*System, opts ...Option) *pather {
+	p := &pather{
+		systems:  make(map[uint64]*System),
+		nodes:    make(map[nodeKey]*node),
+		rtree:    rtreego.NewTree(3, 25, 50),
+		boosters: rtreego.NewTree(3, 25, 50),
+		ship:     s,
+		costFunc: ShortestTime,
+		ctx:      context.Background(),
+		epsilon:  1,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	for _, system := range systems {
+		p.systems[system.ID64] = system
+		p.insert(system)
+	}
+	p.setEndpoints(systems[0], systems[len(systems)-1])
+	p.distance = distance.Distance(p.from.Coordinates, p.to.Coordinates)
+	p.closest = p.distance
+	return p
+}
+
+// testLine returns count systems on X axis, spaced by step LY.
+func testLine(count int, step float64) []*System {
+	var systems []*System
+	for i := 0; i < count; i++ {
+		systems = append(systems, &System{
+			ID64:        uint64(i + 1),
+			Coordinates: r3.Vec{X: float64(i) * step},
+		})
+	}
+	return systems
+}
+
+// pathSystems returns systems of the path waypoints.
+func pathSystems(path []Waypoint) []*System {
+	var systems []*System
+	for _, w := range path {
+		systems = append(systems, w.System)
+	}
+	return systems
+}
+
+// smallTankShip has range of ~67 LY, but fuel for only 2 jumps of 64 LY.
+func smallTankShip() ship.Ship {
+	return ship.New(10, 346.9, 1692.6, 5, 10.5, 878, ship.FSDRating["A"], ship.FSDClass[5])
+}
+
+func TestPathRunsOutOfFuel(t *testing.T) {
+	p := testPather(smallTankShip(), testLine(5, 64))
+
+	_, _, found := p.Path()
+	assert.False(t, found)
+}
+
+func TestPathRefuelsAtScoopable(t *testing.T) {
+	systems := testLine(5, 64)
+	systems[2].Scoopable = true
+	p := testPather(smallTankShip(), systems)
+
+	path, _, found := p.Path()
+	assert.True(t, found)
+	assert.Equal(t, systems, pathSystems(path))
+
+	// Fuel is tracked along the path and refilled at the scoopable star.
+	assert.True(t, path[2].Refuel)
+	assert.True(t, path[2].Arrival.FuelRemaining() < path[1].Arrival.FuelRemaining())
+	assert.EqualValues(t, 10, path[2].Ship.FuelRemaining())
+	assert.InDelta(t, path[2].Arrival.FuelRemaining(), path[4].Arrival.FuelRemaining(), 1e-9)
+}
+
+func TestPathRefuelsAtDestination(t *testing.T) {
+	// Arriving to the scoopable via leaves little fuel for the next leg.
+	systems := testLine(3, 64)
+	systems[2].Scoopable = true
+
+	p := testPather(smallTankShip(), systems)
+	path, cost, found := p.Path()
+	assert.True(t, found)
+	last := path[len(path)-1]
+	assert.False(t, last.Refuel)
+	assert.True(t, last.Ship.FuelRemaining() < 2)
+	assert.EqualValues(t, 2*secondsToJump, cost)
+
+	p = testPather(smallTankShip(), systems, WithDestinationRefuel())
+	path, cost, found = p.Path()
+	assert.True(t, found)
+	last = path[len(path)-1]
+	assert.True(t, last.Refuel)
+	assert.Equal(t, p.ship.FuelRemaining(), last.Ship.FuelRemaining())
+	assert.EqualValues(t, 2*secondsToJump+last.Arrival.SecondsToScoop(), cost)
+
+	// Next leg needs more fuel than was left on arrival.
+	next := testLine(3, 64)
+	_, _, found = testPather(last.Arrival, next).Path()
+	assert.False(t, found)
+	_, _, found = testPather(last.Ship, next).Path()
+	assert.True(t, found)
+}
+
+func TestPathRespectsFuelReserve(t *testing.T) {
+	systems := testLine(5, 64)
+	systems[2].Scoopable = true
+
+	// Arriving to the last, unscoopable system leaves ~1.65 T in the tank.
+	p := testPather(smallTankShip().WithFuelReserve(1), systems)
+	_, _, found := p.Path()
+	assert.True(t, found)
+
+	p = testPather(smallTankShip().WithFuelReserve(2), systems)
+	_, _, found = p.Path()
+	assert.False(t, found)
+	assert.True(t, p.FuelRejected() > 0)
+}
+
+func TestPathNeighborsCarryFuel(t *testing.T) {
+	systems := testLine(3, 64)
+	p := testPather(smallTankShip(), systems)
+
+	neighbors := p.start.neighbors()
+	assert.Len(t, neighbors, 1)
+
+	n := neighbors[0]
+	assert.Equal(t, systems[1], n.system)
+	assert.True(t, n.ship.FuelRemaining() < p.ship.FuelRemaining())
+}
+
+func TestPathNeighborCostIncludesScooping(t *testing.T) {
+	systems := testLine(3, 64)
+	systems[1].Scoopable = true
+	p := testPather(smallTankShip(), systems)
+
+	neighbors := p.start.neighbors()
+	assert.Len(t, neighbors, 2)
+
+	skip := neighbors[0]
+	refuel := neighbors[1]
+	assert.False(t, skip.refuel)
+	assert.True(t, refuel.refuel)
+	assert.Equal(t, p.ship.FuelRemaining(), refuel.ship.FuelRemaining())
+
+	assert.EqualValues(t, secondsToJump, p.start.cost(skip))
+	assert.EqualValues(t, secondsToJump+skip.ship.SecondsToScoop(), p.start.cost(refuel))
+}
+
+func TestPathLadenShipOutOfRange(t *testing.T) {
+	s := ship.New(32, 346.9, 1692.6, 5, 10.5, 878, ship.FSDRating["A"], ship.FSDClass[5])
+
+	p := testPather(s, testLine(3, 64))
+	_, _, found := p.Path()
+	assert.True(t, found)
+
+	p = testPather(s.WithCargo(200), testLine(3, 64))
+	_, _, found = p.Path()
+	assert.False(t, found)
+}
+
+func TestPathWhiteDwarfSupercharge(t *testing.T) {
+	// Gap of 90 LY after the white dwarf is too far for unboosted jump.
+	systems := []*System{
+		{ID64: 1, Coordinates: r3.Vec{X: 0}},
+		{ID64: 2, Coordinates: r3.Vec{X: 60}, WhiteDwarf: true, WhiteDwarfDistance: 10},
+		{ID64: 3, Coordinates: r3.Vec{X: 150}},
+	}
+
+	p := testPather(smallTankShip(), systems)
+	_, _, found := p.Path()
+	assert.False(t, found)
+
+	p = testPather(smallTankShip(), systems, WithWhiteDwarf())
+	path, cost, found := p.Path()
+	assert.True(t, found)
+	assert.Equal(t, systems, pathSystems(path))
+	assert.EqualValues(t, 2*secondsToJump+secondsInSupercruise(10)+secondsToSupercharge+whiteDwarfRisk*secondsToJump, cost)
+}
+
+func TestPathSuperchargesOnlyWhenNeeded(t *testing.T) {
+	systems := []*System{
+		{ID64: 1, Coordinates: r3.Vec{X: 0}},
+		{ID64: 2, Coordinates: r3.Vec{X: 60}, Neutron: true, NeutronDistance: 1000},
+		{ID64: 3, Coordinates: r3.Vec{X: 120}, WhiteDwarf: true, WhiteDwarfDistance: 1000},
+		{ID64: 4, Coordinates: r3.Vec{X: 180}, Scoopable: true},
+	}
+
+	// All the jumps are in range without supercharge.
+	p := testPather(bigTankShip(), systems, WithWhiteDwarf())
+	path, cost, found := p.Path()
+	assert.True(t, found)
+	assert.Equal(t, systems, pathSystems(path))
+	assert.EqualValues(t, 3*secondsToJump, cost)
+	for _, w := range path {
+		assert.False(t, w.Supercharged)
+	}
+
+	// Only the jump to the far target needs the neutron star.
+	far := &System{ID64: 5, Coordinates: r3.Vec{X: 300}}
+	p = testPather(bigTankShip(), []*System{systems[0], systems[1], far}, WithWhiteDwarf())
+	path, cost, found = p.Path()
+	assert.True(t, found)
+	assert.Equal(t, []bool{false, false, true}, []bool{path[0].Supercharged, path[1].Supercharged, path[2].Supercharged})
+	assert.EqualValues(t, 2*secondsToJump+secondsToSuperchargeIn(systems[1]), cost)
+}
+
+func TestPathUsesInjections(t *testing.T) {
+	// Gap of 90 LY is too far for unboosted jump.
+	systems := []*System{
+		{ID64: 1, Coordinates: r3.Vec{X: 0}},
+		{ID64: 2, Coordinates: r3.Vec{X: 60}},
+		{ID64: 3, Coordinates: r3.Vec{X: 150}},
+	}
+
+	p := testPather(smallTankShip(), systems)
+	_, _, found := p.Path()
+	assert.False(t, found)
+
+	// Basic injection is not enough.
+	p = testPather(smallTankShip(), systems, WithInjections(Injections{Basic: 5}))
+	_, _, found = p.Path()
+	assert.False(t, found)
+
+	p = testPather(smallTankShip(), systems, WithInjections(Injections{Basic: 5, Standard: 1, Premium: 1}))
+	path, cost, found := p.Path()
+	assert.True(t, found)
+	assert.Equal(t, systems, pathSystems(path))
+	assert.Equal(t, NoInjection, path[1].Injection)
+	assert.Equal(t, StandardInjection, path[2].Injection)
+	assert.EqualValues(t, 2*secondsToJump+secondsToSynthesise+injectionCost[StandardInjection]*secondsToJump, cost)
+}
+
+func TestPathInjectionsJustBeyondRange(t *testing.T) {
+	r := smallTankShip().JumpRangeWithRemainingFuel()
+	for _, tc := range []struct {
+		injection  Injection
+		injections Injections
+		gap        float64
+	}{
+		// Gaps just beyond the range, inside of 90% of the boosted range.
+		{BasicInjection, Injections{Basic: 1}, 1.05 * r},
+		{PremiumInjection, Injections{Premium: 1}, 1.6 * r},
+	} {
+		// Jump over the gap starts far from both ends of the route, so
+		// systems close to the boosted range are filtered out.
+		systems := testLine(4, 0.95*r)
+		systems = append(systems,
+			&System{ID64: 5, Coordinates: r3.Vec{X: 2.85*r + tc.gap}},
+			&System{ID64: 6, Coordinates: r3.Vec{X: 3.8*r + tc.gap}},
+		)
+		for _, s := range systems {
+			s.Scoopable = true
+		}
+
+		p := testPather(smallTankShip(), systems, WithInjections(tc.injections))
+		path, _, found := p.Path()
+		assert.True(t, found, tc.injection.String())
+		assert.Equal(t, systems, pathSystems(path))
+		assert.Equal(t, tc.injection, path[4].Injection)
+	}
+}
+
+func TestPathInjectionsLimit(t *testing.T) {
+	// Both 90 LY gaps need an injection.
+	systems := []*System{
+		{ID64: 1, Coordinates: r3.Vec{X: 0}},
+		{ID64: 2, Coordinates: r3.Vec{X: 90}, Scoopable: true},
+		{ID64: 3, Coordinates: r3.Vec{X: 180}},
+	}
+
+	p := testPather(smallTankShip(), systems, WithInjections(Injections{Premium: 1}))
+	_, _, found := p.Path()
+	assert.False(t, found)
+
+	p = testPather(smallTankShip(), systems, WithInjections(Injections{Standard: 1, Premium: 1}))
+	path, _, found := p.Path()
+	assert.True(t, found)
+	assert.Equal(t, StandardInjection, path[1].Injection)
+	assert.Equal(t, PremiumInjection, path[2].Injection)
+}
+
+func TestPathAvoidsSystems(t *testing.T) {
+	// Middle system is the only one in range of both ends.
+	systems := []*System{
+		{ID64: 1, Coordinates: r3.Vec{X: 0}},
+		{ID64: 2, Coordinates: r3.Vec{X: 60}},
+		{ID64: 3, Coordinates: r3.Vec{X: 60, Y: 10}},
+		{ID64: 4, Coordinates: r3.Vec{X: 120}},
+	}
+
+	p := testPather(smallTankShip(), systems, WithAvoid(Avoid{2: "is avoided by: 2"}))
+	path, _, found := p.Path()
+	assert.True(t, found)
+	assert.Equal(t, []*System{systems[0], systems[2], systems[3]}, pathSystems(path))
+
+	p = testPather(smallTankShip(), systems, WithAvoid(Avoid{2: "is avoided by: 2", 3: "is avoided by: 3"}))
+	_, _, found = p.Path()
+	assert.False(t, found)
+}
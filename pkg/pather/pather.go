@@ -1,16 +1,16 @@
 package pather
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sync"
+	"time"
 
-	"github.com/lunemec/ed-router/pkg/db/boltdb"
+	"github.com/lunemec/ed-router/pkg/db"
 	"github.com/lunemec/ed-router/pkg/distance"
 	"github.com/lunemec/ed-router/pkg/ship"
-	"github.com/vbauerster/mpb/v5"
 
-	"github.com/beefsack/go-astar"
 	"github.com/dhconnelly/rtreego"
 	"github.com/pkg/errors"
 	"gonum.org/v1/gonum/spatial/r3"
@@ -18,172 +18,487 @@ import (
 
 const maxCost = math.MaxFloat64
 
-const (
-	secondsToJump        float64 = 45
-	secondsToSupercharge float64 = 10
-)
+// DefaultCorridors are corridor widths in multiples of jump range tried
+// when searching for the route.
+var DefaultCorridors = []float64{10, 20, 40}
+
+// ErrNoPath is returned when there is no path in the widest corridor.
+var ErrNoPath = errors.New("no path found")
 
 type Pather interface {
 	From() *System
 	To() *System
 	Distance() float64
-	Path() ([]*System, float64, bool)
+	Path() ([]Waypoint, float64, bool)
+	PathContext(ctx context.Context) ([]Waypoint, float64, error)
+	PathAnytime(ctx context.Context, improve time.Duration, improved func(Improvement)) ([]Waypoint, float64, error)
+	Stats() int
+	FuelRejected() int
+	Reachable() bool
+	Corridor() float64
+}
+
+// Waypoint is a system on the found path with the ship state there.
+type Waypoint struct {
+	*System
+	// Arrival is the ship state right after the jump to this system.
+	Arrival ship.Ship
+	// Ship is the ship state when leaving this system, after refueling.
+	Ship   ship.Ship
+	Refuel bool
+	// Supercharged is true when the jump to this system was supercharged
+	// by neutron star or white dwarf in the previous one.
+	Supercharged bool
+	// Injection is FSD injection needed for the jump to this system.
+	Injection Injection
 }
 
 type pather struct {
 	systems map[uint64]*System
-	store   *boltdb.DB
+	nodes   map[nodeKey]*node
+	store   db.SystemStore
 	rtree   *rtreego.Rtree
+	ship    ship.Ship
+	// boosters are systems which supercharge jumps from them.
+	boosters *rtreego.Rtree
+	// maxJump is the longest possible jump in LY without any boost.
+	maxJump float64
+	// jumps are the fewest jumps from systems to To ignoring fuel, systems
+	// without path to To are missing.
+	jumps map[uint64]int
+	// byID are the nodes indexed by their id.
+	byID []*node
+
+	costFunc CostFunc
+	// whiteDwarf enables white dwarf supercharging.
+	whiteDwarf bool
+	// destinationRefuel makes the ship scoop in To when it is scoopable.
+	destinationRefuel bool
+	// injections are FSD injections the route may use.
+	injections Injections
+	// avoid are ID64s of systems the route must not go through.
+	avoid   Avoid
+	volumes []Volume
+
+	// corridors are corridor widths in multiples of jump range, corridor
+	// is index of the current one.
+	corridors []float64
+	corridor  int
+
+	start *node
 
 	from           *System
 	to             *System
 	distance       float64
 	systemsChecked int
+	// fuelRejected counts jumps rejected for lack of fuel or reserve in
+	// the current corridor.
+	fuelRejected int
+
+	// ctx cancels the running search.
+	ctx      context.Context
+	progress chan<- Progress
+	expanded int
+	// budget is maximum of nodes to expand, 0 is unlimited.
+	budget int
+	// epsilon is weight of the estimate in weighted A*.
+	epsilon float64
+	// bound is cost of the best path found so far by anytime search,
+	// 0 when there is none.
+	bound float64
+	// closest is distance in LY from the closest expanded system to To.
+	closest float64
+}
 
-	pb  *mpb.Progress
-	bar *mpb.Bar
+// Option configures optional pather behaviour.
+type Option func(p *pather)
+
+// WithCostFunc sets what the search optimizes for, ShortestTime by default.
+func WithCostFunc(c CostFunc) Option {
+	return func(p *pather) {
+		p.costFunc = c
+	}
+}
+
+// WithWhiteDwarf enables supercharging from white dwarfs, which is risky
+// so the search only uses them when it pays off.
+func WithWhiteDwarf() Option {
+	return func(p *pather) {
+		p.whiteDwarf = true
+	}
+}
+
+// WithDestinationRefuel makes the ship scoop fuel in the destination when
+// it is scoopable, for routes going on from there.
+func WithDestinationRefuel() Option {
+	return func(p *pather) {
+		p.destinationRefuel = true
+	}
+}
+
+// WithInjections lets the search use FSD injections from the inventory
+// for jumps the ship can not make otherwise.
+func WithInjections(inj Injections) Option {
+	return func(p *pather) {
+		p.injections = inj
+	}
+}
+
+// WithCorridors sets radii of the corridors around the straight line
+// between from and to where the route is searched, in multiples of the
+// ship's jump range. When there is no path, the search is repeated in
+// the next wider corridor.
+func WithCorridors(widths ...float64) Option {
+	return func(p *pather) {
+		p.corridors = widths
+	}
 }
 
-func New(store *boltdb.DB, ship ship.Ship, fromName, toName string) (*pather, error) {
+func New(store db.SystemStore, ship ship.Ship, fromName, toName string, opts ...Option) (*pather, error) {
 	var p = pather{
-		systems: make(map[uint64]*System),
-		store:   store,
+		systems:   make(map[uint64]*System),
+		nodes:     make(map[nodeKey]*node),
+		store:     store,
+		ship:      ship,
+		costFunc:  ShortestTime,
+		corridors: DefaultCorridors,
+		ctx:       context.Background(),
+		epsilon:   1,
+	}
+	for _, opt := range opts {
+		opt(&p)
 	}
 
 	from, err := p.systemByName(fromName)
 	if err != nil {
-		return nil, errors.Wrapf(err, "unable to find system FROM: %s", fromName)
+		return nil, errors.Wrap(err, "invalid FROM system")
 	}
-	from.ship = ship
-	p.from = from
-
 	to, err := p.systemByName(toName)
 	if err != nil {
-		return nil, errors.Wrapf(err, "unable to find system TO: %s", toName)
+		return nil, errors.Wrap(err, "invalid TO system")
+	}
+	if len(p.corridors) == 0 {
+		return nil, errors.New("at least one corridor width is needed")
+	}
+	if p.epsilon < 1 {
+		return nil, errors.Errorf("epsilon must be at least 1, got: %v", p.epsilon)
+	}
+	p.from, p.to = from, to
+	if p.inVolume(to) {
+		return nil, errors.New("destination is inside of keep-out volume")
+	}
+	if reason, ok := p.avoid[to.ID64]; ok {
+		return nil, errors.Errorf("destination %s", reason)
 	}
-	to.ship = ship
-	p.to = to
 	p.distance = distance.Distance(from.Coordinates, to.Coordinates)
+	p.closest = p.distance
+
+	err = p.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (p *pather) Stats() int {
+	return p.systemsChecked
+}
+
+// FuelRejected returns how many jumps were rejected because the ship would
+// run out of fuel or arrive to unscoopable system below the fuel reserve
+// in the last searched corridor.
+func (p *pather) FuelRejected() int {
+	return p.fuelRejected
+}
+
+// Reachable is true when there is path to To in the last searched
+// corridor ignoring fuel, so only fuel can be missing when the search
+// finds none.
+func (p *pather) Reachable() bool {
+	_, ok := p.jumps[p.from.ID64]
+	return ok
+}
 
-	minX := from.Coordinates.X
-	maxX := to.Coordinates.X
-	minY := from.Coordinates.Y
-	maxY := to.Coordinates.Y
-	minZ := from.Coordinates.Z
-	maxZ := to.Coordinates.Z
+func (p *pather) From() *System {
+	return p.from
+}
 
-	if minX > maxX {
-		minX, maxX = maxX, minX
+func (p *pather) To() *System {
+	return p.to
+}
+
+func (p *pather) Distance() float64 {
+	return p.distance
+}
+
+// Path searches for the path, widening the corridor until the path is
+// found or there is no wider corridor.
+func (p *pather) Path() ([]Waypoint, float64, bool) {
+	path, cost, err := p.PathContext(context.Background())
+	if err != nil {
+		if errors.Cause(err) != ErrNoPath {
+			fmt.Printf("ERROR: %+v \n", err)
+		}
+		return nil, 0, false
 	}
-	if minY > maxY {
-		minY, maxY = maxY, minY
+	return path, cost, true
+}
+
+// PathContext is Path which stops when ctx is done, returning ctx.Err().
+// Progress of the search is sent to channel given by WithProgress.
+func (p *pather) PathContext(ctx context.Context) ([]Waypoint, float64, error) {
+	p.ctx = ctx
+	defer p.reportProgress()
+
+	nodes, cost, err := p.astar()
+	for errors.Cause(err) == ErrNoPath && p.corridor < len(p.corridors)-1 {
+		p.corridor++
+		err = p.load()
+		if err != nil {
+			return nil, 0, err
+		}
+		nodes, cost, err = p.astar()
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	// Goal itself is never expanded.
+	p.closest = 0
+	return p.waypoints(nodes), cost, nil
+}
+
+// waypoints replays the jumps along the path, because ship state stored
+// in the search nodes is only accurate to fuelPrecision.
+func (p *pather) waypoints(nodes []*node) []Waypoint {
+	var (
+		waypoints  []Waypoint
+		prev       *node
+		current    = p.ship
+		injections = p.injections
+	)
+	for _, n := range nodes {
+		var (
+			arrival   = current
+			injection = NoInjection
+		)
+		if prev != nil {
+			jumper := node{system: prev.system, ship: current, injections: injections, pather: p}
+			s, inj, err := jumper.jump(n.system, n.supercharged)
+			if err != nil {
+				// Rounding of fuel in nodes made the jump impossible,
+				// use the state the search had.
+				s = n.ship
+			}
+			arrival = s
+			injection = inj
+			injections = injections.Use(inj)
+		}
+		current = arrival
+		if n.refuel {
+			current = arrival.Refuel()
+		}
+		waypoints = append(waypoints, Waypoint{
+			System:  n.system,
+			Arrival: arrival,
+			Ship:    current,
+			Refuel:  n.refuel,
+
+			Supercharged: n.supercharged,
+			Injection:    injection,
+		})
+		prev = n
 	}
-	if minZ > maxZ {
-		minZ, maxZ = maxZ, minZ
+	return waypoints
+}
+
+// Corridor returns radius in LY of the corridor the search is in.
+func (p *pather) Corridor() float64 {
+	if len(p.corridors) == 0 {
+		return 0
 	}
+	return p.corridors[p.corridor] * p.ship.JumpRange()
+}
 
-	var wg sync.WaitGroup
-	systemsChan := make(chan boltdb.System)
+// load loads systems in the current corridor from the store and
+// resets the search.
+func (p *pather) load() error {
+	var (
+		wg          sync.WaitGroup
+		loadErr     error
+		systemsChan = make(chan db.System)
+		min, max    = corridorBounds(p.from.Coordinates, p.to.Coordinates, p.Corridor())
+	)
 	p.rtree = rtreego.NewTree(3, 25, 50)
+	p.boosters = rtreego.NewTree(3, 25, 50)
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		err := p.store.PointsWithinXYZBucketsChan(minX, maxX, minY, maxY, minZ, maxZ, systemsChan)
-		if err != nil {
-			fmt.Printf("error loading systems within %+v\n", err)
-		}
+		loadErr = p.store.StreamSystemsWithin(min, max, systemsChan)
 	}()
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		p.rtree.Insert(p.from)
-		p.rtree.Insert(p.to)
+		p.insert(p.from)
+		p.insert(p.to)
 
 		for system := range systemsChan {
-			coordinates := r3.Vec{X: system.X, Y: system.Y, Z: system.Z}
-			if p.isInCylinder(coordinates) {
-				p.rtree.Insert(&System{
-					Coordinates: coordinates,
-					ID64:        system.ID64,
-					Neutron:     system.IsNeutron,
-					Scoopable:   system.IsScoopable,
-					pather:      &p,
-				})
+			// From and To are already inserted.
+			if system.ID64 == p.from.ID64 || system.ID64 == p.to.ID64 {
+				continue
+			}
+			s := newSystem(system)
+			if p.isInCylinder(s.Coordinates) {
+				p.insert(s)
 			}
 		}
 	}()
 
 	wg.Wait()
+	if loadErr != nil {
+		return errors.Wrapf(loadErr, "unable to load systems within %.0f LY corridor", p.Corridor())
+	}
 
-	// pb := mpb.New(
-	// 	mpb.WithRefreshRate(180 * time.Millisecond),
-	// )
-	// p.pb = pb
-	// bar := pb.AddBar(int64(p.distance),
-	// 	mpb.BarStyle("[=>-|"),
-	// 	mpb.PrependDecorators(
-	// 		decor.CountersNoUnit("% d / % d"),
-	// 	),
-	// 	mpb.AppendDecorators(
-	// 		decor.EwmaETA(decor.ET_STYLE_GO, 90),
-	// 		decor.Name(" ] "),
-	// 	))
-	// p.bar = bar
-	return &p, nil
+	// Neighbors of the already searched nodes may be different now.
+	p.nodes = make(map[nodeKey]*node)
+	p.fuelRejected = 0
+	p.byID = nil
+	p.setEndpoints(p.from, p.to)
+	return nil
 }
 
-func (p *pather) Stats() int {
-	return p.systemsChecked
+// corridorBounds returns corners of the box containing the corridor of
+// radius between from and to.
+func corridorBounds(from, to r3.Vec, radius float64) (r3.Vec, r3.Vec) {
+	min := r3.Vec{X: math.Min(from.X, to.X), Y: math.Min(from.Y, to.Y), Z: math.Min(from.Z, to.Z)}
+	max := r3.Vec{X: math.Max(from.X, to.X), Y: math.Max(from.Y, to.Y), Z: math.Max(from.Z, to.Z)}
+	pad := r3.Vec{X: radius, Y: radius, Z: radius}
+	return min.Sub(pad), max.Add(pad)
 }
 
-func (p *pather) From() *System {
-	return p.from
+// setEndpoints sets the from and to systems of the loaded systems and
+// creates start search node.
+func (p *pather) setEndpoints(from, to *System) {
+	p.from = from
+	p.to = to
+	p.countJumps()
+	p.start = p.node(from, p.ship, false, false, p.injections)
 }
 
-func (p *pather) To() *System {
-	return p.to
+// node returns search node for given system and ship state, creating
+// it if it does not exist yet.
+func (p *pather) node(s *System, sh ship.Ship, refuel, supercharged bool, injections Injections) *node {
+	key := nodeKey{
+		id64:         s.ID64,
+		fuel:         int(sh.FuelRemaining() / fuelPrecision),
+		refuel:       refuel,
+		supercharged: supercharged,
+		injections:   injections,
+	}
+	n, ok := p.nodes[key]
+	if !ok {
+		n = p.add(&node{system: s, ship: sh, refuel: refuel, supercharged: supercharged, injections: injections, pather: p})
+		p.nodes[key] = n
+	}
+	return n
 }
 
-func (p *pather) Distance() float64 {
-	return p.distance
+// estimate estimates cost of path from system s to To. Every jump costs
+// at least MinCost of the cost function and the path has at least the
+// fewest jumps ignoring fuel, so the estimate is never higher than the
+// real cost and the search finds the cheapest path. It is infinite when
+// there is no path.
+func (p *pather) estimate(s *System) float64 {
+	jumps, ok := p.jumps[s.ID64]
+	if !ok {
+		return math.Inf(1)
+	}
+	return float64(jumps) * p.costFunc.MinCost()
 }
 
-func (p *pather) Path() ([]*System, float64, bool) {
-	path, cost, found := astar.Path(p.from, p.to)
-	if !found {
-		return nil, 0, false
+// countJumps searches backwards from To for the fewest jumps to it from
+// the loaded systems, as if every jump had the best case range with any
+// supercharge or FSD injection.
+func (p *pather) countJumps() {
+	p.maxJump = p.ship.MaxJumpRange()
+	injectionBoost := 1.0
+	for _, grade := range p.injections.available() {
+		injectionBoost = math.Max(injectionBoost, grade.Boost())
+	}
+
+	p.jumps = map[uint64]int{p.to.ID64: 0}
+	queue := []*System{p.to}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+
+		// Jumps to s from further away than injection reaches are
+		// supercharged.
+		candidates := append(
+			searchCube(p.rtree, s, p.maxJump*injectionBoost),
+			searchCube(p.boosters, s, p.maxJump*neutronBoost)...,
+		)
+		for _, from := range candidates {
+			if _, ok := p.jumps[from.ID64]; ok {
+				continue
+			}
+			// The route may only start in avoided system.
+			if p.avoided(from) && from != p.from {
+				continue
+			}
+			jumpRange := p.maxJump * math.Max(p.boost(from), injectionBoost)
+			if !isInSphere(from.Coordinates, s.Coordinates, jumpRange) {
+				continue
+			}
+			p.jumps[from.ID64] = p.jumps[s.ID64] + 1
+			queue = append(queue, from)
+		}
 	}
-	var systems []*System
-	for i := len(path) - 1; i >= 0; i-- {
-		systems = append(systems, path[i].(*System))
+}
+
+// insert adds system s to the searched systems.
+func (p *pather) insert(s *System) {
+	p.rtree.Insert(s)
+	if p.boost(s) > 1 {
+		p.boosters.Insert(s)
 	}
-	return systems, cost, true
+}
+
+// add gives n the next node id.
+func (p *pather) add(n *node) *node {
+	n.id = len(p.byID)
+	p.byID = append(p.byID, n)
+	return n
 }
 
 func (p *pather) isInCylinder(point r3.Vec) bool {
-	return isInCylinder(p.from.Coordinates, p.to.Coordinates, p.from.ship.JumpRange()*10, point)
+	return isInCylinder(p.from.Coordinates, p.to.Coordinates, p.Corridor(), point)
+}
+
+// newSystem returns search system of the stored system.
+func newSystem(s db.System) *System {
+	return &System{
+		Coordinates: r3.Vec{X: s.X, Y: s.Y, Z: s.Z},
+		ID64:        s.ID64,
+		Neutron:     s.IsNeutron,
+		Scoopable:   s.IsScoopable,
+
+		NeutronDistance:    s.NeutronDistance,
+		WhiteDwarf:         s.IsWhiteDwarf,
+		WhiteDwarfDistance: s.WhiteDwarfDistance,
+	}
 }
 
 func (p *pather) systemByName(name string) (*System, error) {
-	dbS, err := p.store.SystemByName(name)
+	dbS, err := db.Resolve(p.store, name)
 	if err != nil {
 		return nil, err
 	}
-	s := &System{
-		Coordinates: r3.Vec{
-			X: dbS.Coordinates.X,
-			Y: dbS.Coordinates.Y,
-			Z: dbS.Coordinates.Z,
-		},
-		ID64:      dbS.ID64,
-		Neutron:   boltdb.NeutronInRange(dbS.Bodies),
-		Scoopable: boltdb.ScoopableInRange(dbS.Bodies),
-	}
+	s := newSystem(db.NewSystem(dbS))
 	sc, ok := p.systems[s.ID64]
 	if !ok {
-		s.pather = p
 		p.systems[s.ID64] = s
 		sc = s
 	}
@@ -191,6 +506,23 @@ func (p *pather) systemByName(name string) (*System, error) {
 }
 
 func (p *pather) systemsInRangeOf(s *System, distance float64) ([]*System, error) {
+	var out []*System
+	innerSphereRadius := p.innerSphereRadius(s, distance)
+	for _, target := range p.systemsInCube(s, distance) {
+		if p.reaches(s, target, distance, innerSphereRadius) {
+			out = append(out, target)
+		}
+	}
+
+	return out, nil
+}
+
+// systemsInCube returns systems in cube around s with side 2*distance.
+func (p *pather) systemsInCube(s *System, distance float64) []*System {
+	return searchCube(p.rtree, s, distance)
+}
+
+func searchCube(rtree *rtreego.Rtree, s *System, distance float64) []*System {
 	point := rtreego.Point{
 		s.Coordinates.X - distance,
 		s.Coordinates.Y - distance,
@@ -198,87 +530,65 @@ func (p *pather) systemsInRangeOf(s *System, distance float64) ([]*System, error
 	}
 	distanceX2 := distance * 2
 	bb, _ := rtreego.NewRect(point, []float64{distanceX2, distanceX2, distanceX2})
-	results := p.rtree.SearchIntersect(bb)
+	results := rtree.SearchIntersect(bb)
 
-	var out []*System
-	outerSphereRadius := distance
-	innerSphereRadius := outerSphereRadius * 0.90 // 90% of outerSphereRadius
+	out := make([]*System, 0, len(results))
+	for _, res := range results {
+		out = append(out, res.(*System))
+	}
+	return out
+}
+
+// innerSphereRadius is radius of the sphere around s where systems are
+// not worth jumping to, when jumping up to distance.
+func (p *pather) innerSphereRadius(s *System, distance float64) float64 {
 	// If the target system is within reach, do not filter out the innerSphere
 	// - set its radius to 0.
 	// This is to avoid filtering out the target system.
-	if isInSphere(p.to.Coordinates, s.Coordinates, outerSphereRadius) {
-		innerSphereRadius = 0
+	if isInSphere(p.to.Coordinates, s.Coordinates, distance) {
+		return 0
 	}
-	if isInSphere(p.from.Coordinates, s.Coordinates, outerSphereRadius) {
-		innerSphereRadius = 0
-	}
-	for _, res := range results {
-		target := res.(*System)
-		// If target system is not in range, skip it. (we searched for cube).
-		if !isInSphere(target.Coordinates, s.Coordinates, outerSphereRadius) {
-			continue
-		}
-		// If the target system is inside of the sphere of % radius of the outer
-		// sphere, and is not neutron, skip.
-		if isInSphere(target.Coordinates, s.Coordinates, innerSphereRadius) && !target.Neutron {
-			continue
-		}
-		out = append(out, target)
+	if isInSphere(p.from.Coordinates, s.Coordinates, distance) {
+		return 0
 	}
-
-	return out, nil
+	return distance * 0.90 // 90% of outerSphereRadius
 }
 
-func (p *pather) systemsInRangeOf2(s *System, distance float64) ([]*System, error) {
-	minX := s.Coordinates.X - distance
-	maxX := s.Coordinates.X + distance
-	minY := s.Coordinates.Y - distance
-	maxY := s.Coordinates.Y + distance
-	minZ := s.Coordinates.Z - distance
-	maxZ := s.Coordinates.Z + distance
-	dbSystems, err := p.store.PointsWithinXYZBuckets(minX, maxX, minY, maxY, minZ, maxZ)
-	if err != nil {
-		return nil, errors.Wrapf(err, "unable to get systems in range of %d", s.ID64)
+// reaches is true when jump from s to target is in range of distance and
+// not filtered out.
+func (p *pather) reaches(s, target *System, distance, innerSphereRadius float64) bool {
+	// If target system is not in range, skip it. (we searched for cube).
+	if !isInSphere(target.Coordinates, s.Coordinates, distance) {
+		return false
 	}
-	var filteredDbSystems []boltdb.System
-
-	outerSphereRadius := distance
-	innerSphereRadius := outerSphereRadius * 0.99 // 90% of outerSphereRadius
-	// If the target system is within reach, do not filter out the innerSphere
-	// - set its radius to 0.
-	// This is to avoid filtering out the target system.
-	if isInSphere(p.to.Coordinates, s.Coordinates, outerSphereRadius) {
-		innerSphereRadius = 0
-	}
-	for _, dbSystem := range dbSystems {
-		p := r3.Vec{X: dbSystem.X, Y: dbSystem.Y, Z: dbSystem.Z}
-		// Is actually in the distance (we searched for cube).
-		if isInSphere(p, s.Coordinates, outerSphereRadius) {
-			// Remove most of the center of the sphere, we want only systems within
-			// some % of the max distance.
-			if isInSphere(p, s.Coordinates, innerSphereRadius) {
-				continue
-			}
-			filteredDbSystems = append(filteredDbSystems, dbSystem)
-		}
+	if p.avoided(target) {
+		return false
+	}
+	// If the target system is inside of the sphere of % radius of the outer
+	// sphere, and can not supercharge, skip.
+	if isInSphere(target.Coordinates, s.Coordinates, innerSphereRadius) && !p.supercharges(target) {
+		return false
 	}
+	return true
+}
 
-	var systems []*System
-	for _, dbSystem := range filteredDbSystems {
-		sc, ok := p.systems[dbSystem.ID64]
-		if ok {
-			systems = append(systems, sc)
-		} else {
-			s := &System{
-				pather:      p,
-				Coordinates: r3.Vec{X: dbSystem.X, Y: dbSystem.Y, Z: dbSystem.Z},
-				ID64:        dbSystem.ID64,
-				Neutron:     dbSystem.IsNeutron,
-				Scoopable:   dbSystem.IsScoopable,
-			}
-			p.systems[dbSystem.ID64] = s
-			systems = append(systems, s)
-		}
+func (p *pather) supercharges(s *System) bool {
+	return s.Neutron || (s.WhiteDwarf && p.whiteDwarf)
+}
+
+// boost is the jump range multiplier when jumping from system s.
+func (p *pather) boost(s *System) float64 {
+	switch {
+	case s.Neutron:
+		return neutronBoost
+	case p.whiteDwarfBoost(s):
+		return whiteDwarfBoost
 	}
-	return systems, nil
+	return 1
+}
+
+// whiteDwarfBoost is true when jumps from system s are supercharged by
+// white dwarf. Neutron star is always preferred when there are both.
+func (p *pather) whiteDwarfBoost(s *System) bool {
+	return !s.Neutron && s.WhiteDwarf && p.whiteDwarf
 }
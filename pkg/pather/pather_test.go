@@ -1,464 +1,208 @@
 package pather
 
-// import (
-// 	"errors"
-// 	"testing"
-
-// 	"github.com/lunemec/ed-router/pkg/distance"
-// 	"github.com/lunemec/ed-router/pkg/ship"
-
-// 	"github.com/stretchr/testify/assert"
-// 	"gonum.org/v1/gonum/spatial/r3"
-// )
-
-// type testingSystemsStore struct {
-// 	systems []*System
-// }
-
-// func (t *testingSystemsStore) SystemByID64(id64 int64) (*System, error) {
-// 	for _, s := range t.systems {
-// 		if s.ID64 == id64 {
-// 			return s, nil
-// 		}
-// 	}
-// 	return nil, errors.New("system not found")
-// }
-
-// func (t *testingSystemsStore) SystemByName(name string) (*System, error) {
-// 	for _, s := range t.systems {
-// 		if s.Name == name {
-// 			return s, nil
-// 		}
-// 	}
-// 	return nil, errors.New("system not found")
-// }
-// func (t *testingSystemsStore) SystemID64sAround(point r3.Vec, d float64) ([]int64, error) {
-// 	var id64s []int64
-
-// 	for _, s := range t.systems {
-// 		if distance.Distance(s.Coordinates, point) <= d {
-// 			id64s = append(id64s, s.ID64)
-// 		}
-// 	}
-// 	return id64s, nil
-// }
-
-// type fakeShip struct {
-// 	jumpRange                  float64
-// 	jumpRangeWithRemainingFuel float64
-// 	secondsToScoop             float64
-// }
-
-// func (s fakeShip) Jump(dist float64) (ship.Ship, error) {
-// 	return s, nil
-// }
-
-// func (s fakeShip) JumpRange() float64 {
-// 	return s.jumpRange
-// }
-
-// func (s fakeShip) JumpRangeWithRemainingFuel() float64 {
-// 	return s.jumpRangeWithRemainingFuel
-// }
-// func (s fakeShip) SecondsToScoop() float64 {
-// 	return s.secondsToScoop
-// }
-
-// // TestSystemPatherHappyPath tests 2 systems directly next to each other
-// // with 3rd farther away.
-// func TestSystemPatherHappyPath(t *testing.T) {
-// 	testStore := testingSystemsStore{
-// 		systems: []*System{
-// 			{
-// 				ID64: 1,
-// 				Name: "Sol",
-// 				Coordinates: r3.Vec{
-// 					X: 0,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 2,
-// 				Name: "Sol2",
-// 				Coordinates: r3.Vec{
-// 					X: 1,
-// 					Y: 1,
-// 					Z: 1,
-// 				},
-// 			},
-// 			{
-// 				ID64: 3,
-// 				Name: "Sol3",
-// 				Coordinates: r3.Vec{
-// 					X: 3,
-// 					Y: 3,
-// 					Z: 3,
-// 				},
-// 			},
-// 		},
-// 	}
-// 	s := fakeShip{
-// 		jumpRange:                  10,
-// 		jumpRangeWithRemainingFuel: 10,
-// 	}
-// 	p, err := New(&testStore, s, "Sol", "Sol2")
-// 	assert.NoError(t, err)
-
-// 	path, cost, found := p.Path()
-// 	assert.True(t, found)
-// 	assert.EqualValues(t, 101, cost)
-// 	assert.Equal(t, 2, len(path))
-// 	assert.Equal(t, testStore.systems[0], path[0])
-// 	assert.Equal(t, testStore.systems[1], path[1])
-// }
-
-// func TestSystemPatherImpossible(t *testing.T) {
-// 	testStore := testingSystemsStore{
-// 		systems: []*System{
-// 			{
-// 				ID64: 1,
-// 				Name: "Sol",
-// 				Coordinates: r3.Vec{
-// 					X: 0,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 2,
-// 				Name: "Sol2",
-// 				Coordinates: r3.Vec{
-// 					X: 100,
-// 					Y: 100,
-// 					Z: 100,
-// 				},
-// 			},
-// 		},
-// 	}
-
-// 	s := fakeShip{
-// 		jumpRange:                  10,
-// 		jumpRangeWithRemainingFuel: 10,
-// 	}
-// 	p, err := New(&testStore, s, "Sol", "Sol2")
-// 	assert.NoError(t, err)
-
-// 	_, cost, found := p.Path()
-// 	assert.False(t, found)
-// 	assert.EqualValues(t, 0, cost)
-// }
-
-// // TestSystemPatherNeutron tests 2 systems far enough to be reachable by neutron
-// // charge, but other systems between without neutron charge.
-// func TestSystemPatherNeutron(t *testing.T) {
-// 	testStore := testingSystemsStore{
-// 		systems: []*System{
-// 			{
-// 				ID64: 1,
-// 				Name: "Sol",
-// 				Coordinates: r3.Vec{
-// 					X: 0,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 2,
-// 				Name: "Neutron",
-// 				Coordinates: r3.Vec{
-// 					X: 10,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 				Stars: []Star{
-// 					{
-// 						Type:     "Neutron Star",
-// 						Distance: 0,
-// 					},
-// 				},
-// 			},
-// 			{
-// 				ID64: 3,
-// 				Name: "Target",
-// 				Coordinates: r3.Vec{
-// 					X: 50,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 4,
-// 				Name: "Filler1",
-// 				Coordinates: r3.Vec{
-// 					X: 9,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 5,
-// 				Name: "Filler2",
-// 				Coordinates: r3.Vec{
-// 					X: 19,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 6,
-// 				Name: "Filler3",
-// 				Coordinates: r3.Vec{
-// 					X: 29,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 7,
-// 				Name: "Filler4",
-// 				Coordinates: r3.Vec{
-// 					X: 39,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 8,
-// 				Name: "Filler5",
-// 				Coordinates: r3.Vec{
-// 					X: 49,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 		},
-// 	}
-
-// 	s := fakeShip{
-// 		jumpRange:                  10,
-// 		jumpRangeWithRemainingFuel: 10,
-// 	}
-// 	p, err := New(&testStore, s, "Sol", "Target")
-// 	assert.NoError(t, err)
-
-// 	path, cost, found := p.Path()
-// 	assert.True(t, found)
-
-// 	assert.EqualValues(t, 127, cost)
-// 	assert.Equal(t, 3, len(path))
-// 	assert.Equal(t, testStore.systems[0], path[0])
-// 	assert.Equal(t, testStore.systems[1], path[1])
-// 	assert.Equal(t, testStore.systems[2], path[2])
-// }
-
-// // TestSystemPatherNeutronDistant tests 2 systems far enough to be reachable by neutron
-// // charge, but neutron is distant from jumpin point, so it will be more worth it to choose
-// // more jumps.
-// func TestSystemPatherNeutronDistant(t *testing.T) {
-// 	testStore := testingSystemsStore{
-// 		systems: []*System{
-// 			{
-// 				ID64: 1,
-// 				Name: "Sol",
-// 				Coordinates: r3.Vec{
-// 					X: 0,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 2,
-// 				Name: "Neutron",
-// 				Coordinates: r3.Vec{
-// 					X: 10,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 				Stars: []Star{
-// 					{
-// 						Type:     "Neutron Star",
-// 						Distance: 4790,
-// 					},
-// 				},
-// 			},
-// 			{
-// 				ID64: 3,
-// 				Name: "Target",
-// 				Coordinates: r3.Vec{
-// 					X: 50,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 4,
-// 				Name: "Filler1",
-// 				Coordinates: r3.Vec{
-// 					X: 9,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 5,
-// 				Name: "Filler2",
-// 				Coordinates: r3.Vec{
-// 					X: 19,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 6,
-// 				Name: "Filler3",
-// 				Coordinates: r3.Vec{
-// 					X: 29,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 7,
-// 				Name: "Filler4",
-// 				Coordinates: r3.Vec{
-// 					X: 39,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 			{
-// 				ID64: 8,
-// 				Name: "Filler5",
-// 				Coordinates: r3.Vec{
-// 					X: 49,
-// 					Y: 0,
-// 					Z: 0,
-// 				},
-// 			},
-// 		},
-// 	}
-
-// 	s := fakeShip{
-// 		jumpRange:                  10,
-// 		jumpRangeWithRemainingFuel: 10,
-// 	}
-// 	p, err := New(&testStore, s, "Sol", "Target")
-// 	assert.NoError(t, err)
-
-// 	path, cost, found := p.Path()
-// 	assert.True(t, found)
-
-// 	assert.EqualValues(t, 606, cost)
-// 	assert.Equal(t, 7, len(path))
-// 	assert.Equal(t, testStore.systems[0], path[0])
-// 	assert.Equal(t, testStore.systems[3], path[1])
-// 	assert.Equal(t, testStore.systems[4], path[2])
-// 	assert.Equal(t, testStore.systems[5], path[3])
-// 	assert.Equal(t, testStore.systems[6], path[4])
-// 	assert.Equal(t, testStore.systems[7], path[5])
-// 	assert.Equal(t, testStore.systems[2], path[6])
-// }
-
-// func BenchmarkNeutron(b *testing.B) {
-// 	for n := 0; n < b.N; n++ {
-// 		testStore := testingSystemsStore{
-// 			systems: []*System{
-// 				{
-// 					ID64: 1,
-// 					Name: "Sol",
-// 					Coordinates: r3.Vec{
-// 						X: 0,
-// 						Y: 0,
-// 						Z: 0,
-// 					},
-// 				},
-// 				{
-// 					ID64: 2,
-// 					Name: "Neutron",
-// 					Coordinates: r3.Vec{
-// 						X: 10,
-// 						Y: 0,
-// 						Z: 0,
-// 					},
-// 					Stars: []Star{
-// 						{
-// 							Type:     "Neutron Star",
-// 							Distance: 0,
-// 						},
-// 					},
-// 				},
-// 				{
-// 					ID64: 3,
-// 					Name: "Target",
-// 					Coordinates: r3.Vec{
-// 						X: 50,
-// 						Y: 0,
-// 						Z: 0,
-// 					},
-// 				},
-// 				{
-// 					ID64: 4,
-// 					Name: "Filler1",
-// 					Coordinates: r3.Vec{
-// 						X: 9,
-// 						Y: 0,
-// 						Z: 0,
-// 					},
-// 				},
-// 				{
-// 					ID64: 5,
-// 					Name: "Filler2",
-// 					Coordinates: r3.Vec{
-// 						X: 19,
-// 						Y: 0,
-// 						Z: 0,
-// 					},
-// 				},
-// 				{
-// 					ID64: 6,
-// 					Name: "Filler3",
-// 					Coordinates: r3.Vec{
-// 						X: 29,
-// 						Y: 0,
-// 						Z: 0,
-// 					},
-// 				},
-// 				{
-// 					ID64: 7,
-// 					Name: "Filler4",
-// 					Coordinates: r3.Vec{
-// 						X: 39,
-// 						Y: 0,
-// 						Z: 0,
-// 					},
-// 				},
-// 				{
-// 					ID64: 8,
-// 					Name: "Filler5",
-// 					Coordinates: r3.Vec{
-// 						X: 49,
-// 						Y: 0,
-// 						Z: 0,
-// 					},
-// 				},
-// 			},
-// 		}
-
-// 		s := fakeShip{
-// 			jumpRange:                  10,
-// 			jumpRangeWithRemainingFuel: 10,
-// 		}
-// 		p, err := New(&testStore, s, "Sol", "Target")
-// 		assert.NoError(b, err)
-
-// 		path, cost, found := p.Path()
-// 		assert.True(b, found)
-
-// 		assert.EqualValues(b, 127, cost)
-// 		assert.Equal(b, 3, len(path))
-// 		assert.Equal(b, testStore.systems[0], path[0])
-// 		assert.Equal(b, testStore.systems[1], path[1])
-// 		assert.Equal(b, testStore.systems[2], path[2])
-// 	}
-// }
+import (
+	"testing"
+
+	"github.com/lunemec/ed-router/pkg/db"
+	"github.com/lunemec/ed-router/pkg/db/memory"
+	"github.com/lunemec/ed-router/pkg/models/dump"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// testStar returns stored system with main star of subType.
+func testStar(id64 uint64, name string, coordinates r3.Vec, subType string) dump.System {
+	return dump.System{
+		ID64:        id64,
+		Name:        name,
+		Coordinates: coordinates,
+		Bodies: []dump.Body{
+			{Name: name, Type: "Star", SubType: subType},
+		},
+	}
+}
+
+const (
+	scoopable  = "K (Yellow-Orange) Star"
+	brownDwarf = "L (Brown dwarf) Star"
+)
+
+// waypointIDs returns ID64s of the path systems.
+func waypointIDs(path []Waypoint) []uint64 {
+	var ids []uint64
+	for _, w := range path {
+		ids = append(ids, w.ID64)
+	}
+	return ids
+}
+
+func TestNewUnknownSystem(t *testing.T) {
+	store := memory.New(testStar(1, "Sol", r3.Vec{}, scoopable))
+
+	_, err := New(store, bigTankShip(), "Sol", "Sol2")
+	assert.True(t, errors.Is(err, db.ErrNotFound))
+	assert.Contains(t, err.Error(), "did you mean: Sol?")
+	_, err = New(store, bigTankShip(), "Sol2", "Sol")
+	assert.True(t, errors.Is(err, db.ErrNotFound))
+}
+
+func TestPatherHappyPath(t *testing.T) {
+	store := memory.New(
+		testStar(1, "Sol", r3.Vec{}, scoopable),
+		testStar(2, "Sol2", r3.Vec{X: 10}, scoopable),
+		testStar(3, "Sol3", r3.Vec{X: 200}, scoopable),
+	)
+
+	// Names are case insensitive.
+	p, err := New(store, bigTankShip(), "sol", "SOL2")
+	assert.NoError(t, err)
+
+	path, cost, found := p.Path()
+	assert.True(t, found)
+	assert.Greater(t, cost, 0.0)
+	assert.Equal(t, []uint64{1, 2}, waypointIDs(path))
+}
+
+func TestPatherImpossible(t *testing.T) {
+	store := memory.New(
+		testStar(1, "Sol", r3.Vec{}, scoopable),
+		testStar(2, "Sol2", r3.Vec{X: 1000}, scoopable),
+	)
+
+	p, err := New(store, bigTankShip(), "Sol", "Sol2")
+	assert.NoError(t, err)
+
+	_, cost, found := p.Path()
+	assert.False(t, found)
+	assert.EqualValues(t, 0, cost)
+	// Fuel is not what is missing.
+	assert.False(t, p.Reachable())
+	assert.Zero(t, p.FuelRejected())
+}
+
+func TestPatherFuelRejectedPerCorridor(t *testing.T) {
+	// The ship would arrive below the fuel reserve in every corridor.
+	store := memory.New(
+		testStar(1, "Sol", r3.Vec{}, scoopable),
+		testStar(2, "A", r3.Vec{X: 64}, brownDwarf),
+		testStar(3, "B", r3.Vec{X: 128}, brownDwarf),
+		testStar(4, "C", r3.Vec{X: 192}, brownDwarf),
+		testStar(5, "Target", r3.Vec{X: 256}, brownDwarf),
+	)
+
+	p, err := New(store, smallTankShip().WithFuelReserve(2), "Sol", "Target", WithCorridors(1))
+	assert.NoError(t, err)
+	_, _, found := p.Path()
+	assert.False(t, found)
+	assert.True(t, p.Reachable())
+	rejected := p.FuelRejected()
+	assert.Greater(t, rejected, 0)
+
+	p, err = New(store, smallTankShip().WithFuelReserve(2), "Sol", "Target", WithCorridors(1, 2))
+	assert.NoError(t, err)
+	_, _, found = p.Path()
+	assert.False(t, found)
+	assert.Equal(t, rejected, p.FuelRejected())
+}
+
+// TestPatherNeutron tests systems far enough to be reachable in 1 jump
+// only with neutron supercharge, and other systems between without it.
+func TestPatherNeutron(t *testing.T) {
+	store := memory.New(
+		testStar(1, "Sol", r3.Vec{}, scoopable),
+		testStar(2, "Neutron", r3.Vec{X: 60}, "Neutron Star"),
+		testStar(3, "Target", r3.Vec{X: 260}, scoopable),
+		testStar(4, "A", r3.Vec{X: 120}, scoopable),
+		testStar(5, "B", r3.Vec{X: 180}, scoopable),
+		testStar(6, "C", r3.Vec{X: 240}, scoopable),
+	)
+
+	p, err := New(store, bigTankShip(), "Sol", "Target")
+	assert.NoError(t, err)
+
+	path, _, found := p.Path()
+	assert.True(t, found)
+	assert.Equal(t, []uint64{1, 2, 3}, waypointIDs(path))
+}
+
+func TestPatherWidensCorridor(t *testing.T) {
+	s := bigTankShip()
+	r := s.JumpRange()
+	// The target is out of range, the only system in range of both is
+	// outside of the narrow corridor.
+	store := memory.New(
+		testStar(1, "Sol", r3.Vec{}, scoopable),
+		testStar(2, "Detour", r3.Vec{X: 0.8 * r, Y: 0.55 * r}, scoopable),
+		testStar(3, "Target", r3.Vec{X: 1.6 * r}, scoopable),
+	)
+
+	p, err := New(store, s, "Sol", "Target", WithCorridors(0.5, 10))
+	assert.NoError(t, err)
+	// Target is out of reach in the narrow corridor whatever the fuel.
+	assert.False(t, p.Reachable())
+
+	path, _, found := p.Path()
+	assert.True(t, found)
+	assert.Equal(t, []uint64{1, 2, 3}, waypointIDs(path))
+	assert.InDelta(t, 10*r, p.Corridor(), 1e-9)
+	assert.True(t, p.Reachable())
+}
+
+func TestPatherAvoidsSector(t *testing.T) {
+	store := memory.New(
+		testStar(1, "Sol", r3.Vec{}, scoopable),
+		testStar(2, "Avoided AB-C d1", r3.Vec{X: 60}, scoopable),
+		testStar(3, "Avoided", r3.Vec{X: 60, Y: 10}, scoopable),
+		testStar(4, "Other", r3.Vec{X: 60, Y: -10}, scoopable),
+		testStar(5, "Target", r3.Vec{X: 120}, scoopable),
+	)
+
+	// System name does not avoid the sector of the same name.
+	avoid, err := ResolveAvoid(store, []string{"avoided"}, []string{"all"})
+	assert.NoError(t, err)
+	assert.Equal(t, Avoid{3: "is avoided by: avoided"}, avoid)
+
+	avoid, err = ResolveAvoid(store, []string{"sector:avoided", "3"}, []string{"all"})
+	assert.NoError(t, err)
+	assert.Equal(t, Avoid{2: "is avoided by: sector:avoided", 3: "is avoided by: 3"}, avoid)
+	p, err := New(store, bigTankShip(), "Sol", "Target", WithAvoid(avoid))
+	assert.NoError(t, err)
+	path, _, found := p.Path()
+	assert.True(t, found)
+	assert.Equal(t, []uint64{1, 4, 5}, waypointIDs(path))
+
+	avoid, err = ResolveAvoid(store, []string{"Target"}, []string{"all"})
+	assert.NoError(t, err)
+	_, err = New(store, bigTankShip(), "Sol", "Target", WithAvoid(avoid))
+	assert.EqualError(t, err, "destination is avoided by: Target")
+	_, err = ResolveAvoid(store, []string{"Unknown"}, nil)
+	assert.Error(t, err)
+}
+
+func TestPatherAvoidsPermitLocked(t *testing.T) {
+	store := memory.New(
+		testStar(1, "Start", r3.Vec{}, scoopable),
+		// Systems named by locked system are not in its sector.
+		testStar(2, "Sol A", r3.Vec{X: 60}, scoopable),
+		testStar(3, "Sirius", r3.Vec{X: 60, Y: 10}, scoopable),
+		testStar(4, "Bleia1 AB-C d1", r3.Vec{X: 60, Y: -10}, scoopable),
+		testStar(5, "Target", r3.Vec{X: 120}, scoopable),
+	)
+
+	avoid, err := ResolveAvoid(store, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Avoid{3: "needs permit for: Sirius", 4: "needs permit for: Bleia1"}, avoid)
+	p, err := New(store, bigTankShip(), "Start", "Target", WithAvoid(avoid))
+	assert.NoError(t, err)
+	path, _, found := p.Path()
+	assert.True(t, found)
+	assert.Equal(t, []uint64{1, 2, 5}, waypointIDs(path))
+	_, err = New(store, bigTankShip(), "Start", "Sirius", WithAvoid(avoid))
+	assert.EqualError(t, err, "destination needs permit for: Sirius")
+
+	avoid, err = ResolveAvoid(store, nil, []string{"sirius", "bleia1"})
+	assert.NoError(t, err)
+	assert.Empty(t, avoid)
+}
@@ -0,0 +1,66 @@
+package pather
+
+import (
+	"github.com/lunemec/ed-router/pkg/distance"
+)
+
+// progressInterval is number of expanded nodes between progress events.
+const progressInterval = 1000
+
+// Progress is progress event of the search.
+type Progress struct {
+	// Expanded is number of search nodes expanded so far.
+	Expanded int
+	// Closest is distance in LY from the closest expanded system to the
+	// target.
+	Closest float64
+	// Distance is distance in LY from the start to the target.
+	Distance float64
+	// Corridor is radius in LY of the corridor being searched.
+	Corridor float64
+}
+
+// WithProgress sends progress events of the search to ch. Events are
+// dropped while ch is not ready, so slow receiver does not slow down the
+// search. The channel is not closed by the pather.
+func WithProgress(ch chan<- Progress) Option {
+	return func(p *pather) {
+		p.progress = ch
+	}
+}
+
+// expand records system s as expanded and reports progress. It returns
+// error when the search was cancelled or is over budget and s must not be
+// expanded.
+func (p *pather) expand(s *System) error {
+	if p.ctx.Err() != nil {
+		return p.ctx.Err()
+	}
+	if p.budget > 0 && p.expanded >= p.budget {
+		return ErrBudgetExceeded
+	}
+	p.expanded++
+	d := distance.Distance(s.Coordinates, p.to.Coordinates)
+	if d < p.closest {
+		p.closest = d
+	}
+	if p.expanded%progressInterval == 0 {
+		p.reportProgress()
+	}
+	return nil
+}
+
+func (p *pather) reportProgress() {
+	if p.progress == nil {
+		return
+	}
+	select {
+	case p.progress <- Progress{
+		Expanded: p.expanded,
+		Closest:  p.closest,
+		Distance: p.distance,
+		Corridor: p.Corridor(),
+	}:
+	default:
+	}
+}
@@ -0,0 +1,40 @@
+package pather
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathContextReportsProgress(t *testing.T) {
+	progress := make(chan Progress, 1)
+	p := testPather(smallTankShip(), testLine(5, 20), WithProgress(progress))
+
+	_, _, err := p.PathContext(context.Background())
+	assert.NoError(t, err)
+
+	// Progress is reported when the search ends.
+	event := <-progress
+	assert.NotZero(t, event.Expanded)
+	assert.EqualValues(t, 80, event.Distance)
+	assert.Zero(t, event.Closest)
+}
+
+func TestPathContextCancelled(t *testing.T) {
+	p := testPather(smallTankShip(), testLine(5, 20))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	path, _, err := p.PathContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, path)
+	assert.Zero(t, p.expanded)
+}
+
+func TestPathNoPath(t *testing.T) {
+	p := testPather(smallTankShip(), testLine(2, 100))
+
+	_, _, err := p.PathContext(context.Background())
+	assert.Equal(t, ErrNoPath, err)
+}
@@ -8,8 +8,8 @@ import (
 )
 
 func TestIsInSphere(t *testing.T) {
-	assert.True(t, isInSphere(r3.Vec{0, 0, 0}, r3.Vec{0, 0, 0}, 1))
-	assert.False(t, isInSphere(r3.Vec{1, 1, 1}, r3.Vec{0, 0, 0}, 1))
+	assert.True(t, isInSphere(r3.Vec{X: 0, Y: 0, Z: 0}, r3.Vec{X: 0, Y: 0, Z: 0}, 1))
+	assert.False(t, isInSphere(r3.Vec{X: 1, Y: 1, Z: 1}, r3.Vec{X: 0, Y: 0, Z: 0}, 1))
 }
 
 var Out bool
@@ -17,7 +17,7 @@ var Out bool
 func BenchmarkIsInSphere(b *testing.B) {
 	// 22920224	        52.2 ns/op	       0 B/op	       0 allocs/op
 	for i := 0; i < b.N; i++ {
-		Out = isInSphere(r3.Vec{0, 0, 0}, r3.Vec{0, 0, 0}, 1)
-		Out = isInSphere(r3.Vec{1, 1, 1}, r3.Vec{0, 0, 0}, 1)
+		Out = isInSphere(r3.Vec{X: 0, Y: 0, Z: 0}, r3.Vec{X: 0, Y: 0, Z: 0}, 1)
+		Out = isInSphere(r3.Vec{X: 1, Y: 1, Z: 1}, r3.Vec{X: 0, Y: 0, Z: 0}, 1)
 	}
 }
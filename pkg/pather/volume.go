@@ -0,0 +1,135 @@
+package pather
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/spatial/r3"
+	"gopkg.in/yaml.v3"
+)
+
+// Volume is part of space the route must keep out of.
+type Volume interface {
+	Contains(p r3.Vec) bool
+}
+
+// Sphere is volume with center and radius in LY.
+type Sphere struct {
+	Center r3.Vec
+	Radius float64
+}
+
+// Contains is true when p is inside of the sphere.
+func (s Sphere) Contains(p r3.Vec) bool {
+	return isInSphere(p, s.Center, s.Radius)
+}
+
+// Box is axis-aligned box volume between Min and Max corners.
+type Box struct {
+	Min, Max r3.Vec
+}
+
+// Contains is true when p is inside of the box.
+func (b Box) Contains(p r3.Vec) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X &&
+		p.Y >= b.Min.Y && p.Y <= b.Max.Y &&
+		p.Z >= b.Min.Z && p.Z <= b.Max.Z
+}
+
+// Cylinder is volume between Start and End with Radius in LY.
+type Cylinder struct {
+	Start, End r3.Vec
+	Radius     float64
+}
+
+// Contains is true when p is inside of the cylinder.
+func (c Cylinder) Contains(p r3.Vec) bool {
+	return isInCylinder(c.Start, c.End, c.Radius, p)
+}
+
+// volumesFile is the keep-out volumes file, eg:
+//
+//	volumes:
+//	  - name: Thargoid war zone
+//	    sphere: {center: [-78.6, -149.6, -340.5], radius: 50}
+//	  - box: {min: [0, 0, 0], max: [100, 100, 100]}
+//	  - cylinder: {start: [0, 0, 0], end: [0, 0, 1000], radius: 20}
+type volumesFile struct {
+	Volumes []struct {
+		Name   string `yaml:"name"`
+		Sphere *struct {
+			Center [3]float64 `yaml:"center"`
+			Radius float64    `yaml:"radius"`
+		} `yaml:"sphere"`
+		Box *struct {
+			Min [3]float64 `yaml:"min"`
+			Max [3]float64 `yaml:"max"`
+		} `yaml:"box"`
+		Cylinder *struct {
+			Start  [3]float64 `yaml:"start"`
+			End    [3]float64 `yaml:"end"`
+			Radius float64    `yaml:"radius"`
+		} `yaml:"cylinder"`
+	} `yaml:"volumes"`
+}
+
+func vec(v [3]float64) r3.Vec {
+	return r3.Vec{X: v[0], Y: v[1], Z: v[2]}
+}
+
+// LoadVolumes reads keep-out volumes from YAML file.
+func LoadVolumes(r io.Reader) ([]Volume, error) {
+	var f volumesFile
+	err := yaml.NewDecoder(r).Decode(&f)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "unable to decode volumes")
+	}
+
+	var volumes []Volume
+	for i, v := range f.Volumes {
+		name := v.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		switch {
+		case v.Sphere != nil && v.Box == nil && v.Cylinder == nil:
+			if v.Sphere.Radius <= 0 {
+				return nil, errors.Errorf("volume %s: sphere radius must be positive", name)
+			}
+			volumes = append(volumes, Sphere{Center: vec(v.Sphere.Center), Radius: v.Sphere.Radius})
+		case v.Box != nil && v.Sphere == nil && v.Cylinder == nil:
+			min, max := vec(v.Box.Min), vec(v.Box.Max)
+			if min.X > max.X || min.Y > max.Y || min.Z > max.Z {
+				return nil, errors.Errorf("volume %s: box min must not be greater than max", name)
+			}
+			volumes = append(volumes, Box{Min: min, Max: max})
+		case v.Cylinder != nil && v.Sphere == nil && v.Box == nil:
+			if v.Cylinder.Radius <= 0 {
+				return nil, errors.Errorf("volume %s: cylinder radius must be positive", name)
+			}
+			volumes = append(volumes, Cylinder{Start: vec(v.Cylinder.Start), End: vec(v.Cylinder.End), Radius: v.Cylinder.Radius})
+		default:
+			return nil, errors.Errorf("volume %s: must be exactly one of sphere, box or cylinder", name)
+		}
+	}
+	return volumes, nil
+}
+
+// WithVolumes makes the route keep out of the volumes, no system inside
+// of any of them is visited.
+func WithVolumes(volumes ...Volume) Option {
+	return func(p *pather) {
+		p.volumes = append(p.volumes, volumes...)
+	}
+}
+
+// inVolume is true when s is inside of any keep-out volume.
+func (p *pather) inVolume(s *System) bool {
+	for _, v := range p.volumes {
+		if v.Contains(s.Coordinates) {
+			return true
+		}
+	}
+	return false
+}
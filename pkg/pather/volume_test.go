@@ -0,0 +1,69 @@
+package pather
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestLoadVolumes(t *testing.T) {
+	volumes, err := LoadVolumes(strings.NewReader(`
+volumes:
+  - name: war zone
+    sphere: {center: [1, 2, 3], radius: 50}
+  - box: {min: [0, 0, 0], max: [100, 100, 100]}
+  - cylinder: {start: [0, 0, 0], end: [0, 0, 1000], radius: 20}
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Volume{
+		Sphere{Center: r3.Vec{X: 1, Y: 2, Z: 3}, Radius: 50},
+		Box{Max: r3.Vec{X: 100, Y: 100, Z: 100}},
+		Cylinder{End: r3.Vec{Z: 1000}, Radius: 20},
+	}, volumes)
+
+	volumes, err = LoadVolumes(strings.NewReader(``))
+	assert.NoError(t, err)
+	assert.Empty(t, volumes)
+
+	_, err = LoadVolumes(strings.NewReader(`volumes: [{name: empty}]`))
+	assert.EqualError(t, err, "volume empty: must be exactly one of sphere, box or cylinder")
+	_, err = LoadVolumes(strings.NewReader(`volumes: [{sphere: {radius: 0}}]`))
+	assert.EqualError(t, err, "volume #1: sphere radius must be positive")
+	_, err = LoadVolumes(strings.NewReader(`volumes: [{box: {min: [1, 0, 0]}}]`))
+	assert.Error(t, err)
+}
+
+func TestVolumesContain(t *testing.T) {
+	box := Box{Min: r3.Vec{X: -1, Y: -1, Z: -1}, Max: r3.Vec{X: 1, Y: 1, Z: 1}}
+	assert.True(t, box.Contains(r3.Vec{}))
+	assert.True(t, box.Contains(r3.Vec{X: 1, Y: -1}))
+	assert.False(t, box.Contains(r3.Vec{Z: 1.1}))
+
+	sphere := Sphere{Radius: 10}
+	assert.True(t, sphere.Contains(r3.Vec{X: 5, Y: 5}))
+	assert.False(t, sphere.Contains(r3.Vec{X: 10}))
+
+	cylinder := Cylinder{End: r3.Vec{X: 100}, Radius: 5}
+	assert.True(t, cylinder.Contains(r3.Vec{X: 50, Y: 4}))
+	assert.False(t, cylinder.Contains(r3.Vec{X: 101}))
+}
+
+func TestPathKeepsOutOfVolumes(t *testing.T) {
+	systems := []*System{
+		{ID64: 1, Coordinates: r3.Vec{X: 0}},
+		{ID64: 2, Coordinates: r3.Vec{X: 60}},
+		{ID64: 3, Coordinates: r3.Vec{X: 60, Y: 10}},
+		{ID64: 4, Coordinates: r3.Vec{X: 120}},
+	}
+
+	p := testPather(smallTankShip(), systems, WithVolumes(Sphere{Center: r3.Vec{X: 60}, Radius: 5}))
+	path, _, found := p.Path()
+	assert.True(t, found)
+	assert.Equal(t, []*System{systems[0], systems[2], systems[3]}, pathSystems(path))
+
+	p = testPather(smallTankShip(), systems, WithVolumes(Box{Min: r3.Vec{X: 50, Y: -20}, Max: r3.Vec{X: 70, Y: 20}}))
+	_, _, found = p.Path()
+	assert.False(t, found)
+}
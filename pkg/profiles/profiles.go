@@ -0,0 +1,165 @@
+package profiles
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/lunemec/ed-router/pkg/config"
+	"github.com/lunemec/ed-router/pkg/ship"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Add is the entrypoint for ship add command, it expects 1 argument [name].
+// Ship parameters are read from --loadout file and/or individual flags,
+// flags take precedence.
+func Add(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	path := config.Path(cmd)
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Ships[name]; ok && !force {
+		return errors.Errorf("ship profile %s already exists, use --force to overwrite it", name)
+	}
+
+	l, err := loadoutFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	err = l.Validate()
+	if err != nil {
+		return errors.Wrap(err, "invalid ship")
+	}
+
+	if cfg.Ships == nil {
+		cfg.Ships = make(map[string]ship.Loadout)
+	}
+	cfg.Ships[name] = l
+	err = cfg.Save(path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Ship %s saved to %s, jump range: %.2f LY\n", name, path, l.Ship().JumpRange())
+	return nil
+}
+
+// List is the entrypoint for ship list command.
+func List(cmd *cobra.Command, args []string) error {
+	cfg, err := config.FromCommand(cmd)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for name := range cfg.Ships {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Printf("%s\t%.2f LY\n", name, cfg.Ships[name].Ship().JumpRange())
+	}
+	return nil
+}
+
+// Remove is the entrypoint for ship remove command, it expects 1 argument [name].
+func Remove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	path := config.Path(cmd)
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	if _, err := cfg.Ship(name); err != nil {
+		return err
+	}
+	delete(cfg.Ships, name)
+	return cfg.Save(path)
+}
+
+// Show is the entrypoint for ship show command, it expects 1 argument [name].
+func Show(cmd *cobra.Command, args []string) error {
+	cfg, err := config.FromCommand(cmd)
+	if err != nil {
+		return err
+	}
+	l, err := cfg.Ship(args[0])
+	if err != nil {
+		return err
+	}
+
+	err = yaml.NewEncoder(os.Stdout).Encode(l)
+	if err != nil {
+		return errors.Wrap(err, "unable to print ship")
+	}
+	fmt.Printf("jump range: %.2f LY\n", l.Ship().JumpRange())
+	return nil
+}
+
+func loadoutFromFlags(cmd *cobra.Command) (ship.Loadout, error) {
+	var l ship.Loadout
+	flags := cmd.Flags()
+
+	loadoutFile, err := flags.GetString("loadout")
+	if err != nil {
+		return l, err
+	}
+	if loadoutFile != "" {
+		f, err := os.Open(loadoutFile)
+		if err != nil {
+			return l, errors.Wrap(err, "unable to open ship loadout")
+		}
+		defer f.Close()
+
+		l, err = ship.FromLoadout(f)
+		if err != nil {
+			return l, errors.Wrapf(err, "unable to load ship from: %s", loadoutFile)
+		}
+	}
+
+	// FSD class and rating set the stock FSD stats, so they go first and
+	// may be overridden by engineered values below.
+	if flags.Changed("fsd-class") || flags.Changed("fsd-rating") {
+		class, _ := flags.GetInt("fsd-class")
+		rating, _ := flags.GetString("fsd-rating")
+		if !flags.Changed("fsd-class") {
+			class = l.FSDClass
+		}
+		if !flags.Changed("fsd-rating") {
+			rating = l.FSDRating
+		}
+		err = l.SetFSD(class, rating)
+		if err != nil {
+			return l, err
+		}
+	}
+
+	for flag, value := range map[string]*float64{
+		"fuel-tank":         &l.FuelTank,
+		"mass":              &l.Mass,
+		"fsd-optimal-mass":  &l.FSDOptimalMass,
+		"max-fuel-per-jump": &l.MaxFuelPerJump,
+		"guardian-booster":  &l.GuardianFSDBoosterRange,
+		"scoop-rate":        &l.ScoopRate,
+		"reservoir":         &l.Reservoir,
+	} {
+		if !flags.Changed(flag) {
+			continue
+		}
+		*value, err = flags.GetFloat64(flag)
+		if err != nil {
+			return l, err
+		}
+	}
+	return l, nil
+}
@@ -0,0 +1,151 @@
+package profiles
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lunemec/ed-router/pkg/config"
+	"github.com/lunemec/ed-router/pkg/ship"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// testCommand returns command with ship add flags parsed from args.
+func testCommand(t *testing.T, configPath string, args ...string) *cobra.Command {
+	cmd := &cobra.Command{}
+	flags := cmd.Flags()
+	flags.String("config", configPath, "")
+	flags.String("loadout", "", "")
+	flags.Bool("force", false, "")
+	flags.Int("fsd-class", 0, "")
+	flags.String("fsd-rating", "", "")
+	for _, flag := range []string{"fuel-tank", "mass", "fsd-optimal-mass", "max-fuel-per-jump", "guardian-booster", "scoop-rate", "reservoir"} {
+		flags.Float64(flag, 0, "")
+	}
+	assert.NoError(t, flags.Parse(args))
+	return cmd
+}
+
+func TestLoadoutFromFlags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ed-router")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	loadoutFile := filepath.Join(dir, "loadout.json")
+	assert.NoError(t, ioutil.WriteFile(loadoutFile, []byte(`{
+		"event": "Loadout",
+		"UnladenMass": 346.9,
+		"FuelCapacity": {"Main": 32, "Reserve": 0.63},
+		"Modules": [
+			{"Slot": "FrameShiftDrive", "Item": "int_hyperdrive_size5_class5"}
+		]
+	}`), 0644))
+
+	stock5A := ship.Loadout{}
+	assert.NoError(t, stock5A.SetFSD(5, "A"))
+
+	for _, tc := range []struct {
+		name string
+		args []string
+		want func() ship.Loadout
+		err  bool
+	}{
+		{
+			name: "no flags",
+			want: func() ship.Loadout { return ship.Loadout{} },
+		},
+		{
+			name: "stock FSD",
+			args: []string{"--fsd-class", "5", "--fsd-rating", "A", "--fuel-tank", "32", "--mass", "346.9"},
+			want: func() ship.Loadout {
+				l := stock5A
+				l.FuelTank = 32
+				l.Mass = 346.9
+				return l
+			},
+		},
+		{
+			name: "engineered FSD",
+			args: []string{"--fsd-class", "5", "--fsd-rating", "A", "--fsd-optimal-mass", "1692.6", "--guardian-booster", "10.5"},
+			want: func() ship.Loadout {
+				l := stock5A
+				l.FSDOptimalMass = 1692.6
+				l.GuardianFSDBoosterRange = 10.5
+				return l
+			},
+		},
+		{
+			name: "unknown FSD",
+			args: []string{"--fsd-class", "9", "--fsd-rating", "A"},
+			err:  true,
+		},
+		{
+			name: "loadout overridden by flags",
+			args: []string{"--loadout", loadoutFile, "--fuel-tank", "16", "--scoop-rate", "878"},
+			want: func() ship.Loadout {
+				l := stock5A
+				l.FuelTank = 16
+				// Unladen mass with full tank.
+				l.Mass = 378.9
+				l.Reservoir = 0.63
+				l.ScoopRate = 878
+				return l
+			},
+		},
+		{
+			name: "missing loadout",
+			args: []string{"--loadout", filepath.Join(dir, "missing.json")},
+			err:  true,
+		},
+	} {
+		l, err := loadoutFromFlags(testCommand(t, "", tc.args...))
+		if tc.err {
+			assert.Error(t, err, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.want(), l, tc.name)
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ed-router")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+
+	shipFlags := []string{"--fsd-class", "5", "--fsd-rating", "A", "--fuel-tank", "32", "--mass", "346.9"}
+	for _, tc := range []struct {
+		name    string
+		run     func(cmd *cobra.Command, args []string) error
+		profile string
+		args    []string
+		err     bool
+		ships   []string
+	}{
+		{name: "add", run: Add, profile: "conda", args: shipFlags, ships: []string{"conda"}},
+		{name: "add existing", run: Add, profile: "conda", args: shipFlags, err: true, ships: []string{"conda"}},
+		{name: "overwrite", run: Add, profile: "conda", args: append([]string{"--force"}, shipFlags...), ships: []string{"conda"}},
+		{name: "add invalid", run: Add, profile: "krait", args: []string{"--fuel-tank", "32"}, err: true, ships: []string{"conda"}},
+		{name: "add another", run: Add, profile: "krait", args: shipFlags, ships: []string{"conda", "krait"}},
+		{name: "remove", run: Remove, profile: "conda", ships: []string{"krait"}},
+		{name: "remove missing", run: Remove, profile: "conda", err: true, ships: []string{"krait"}},
+	} {
+		err := tc.run(testCommand(t, path, tc.args...), []string{tc.profile})
+		if tc.err {
+			assert.Error(t, err, tc.name)
+		} else {
+			assert.NoError(t, err, tc.name)
+		}
+
+		cfg, err := config.Load(path)
+		assert.NoError(t, err)
+		var ships []string
+		for name := range cfg.Ships {
+			ships = append(ships, name)
+		}
+		assert.ElementsMatch(t, tc.ships, ships, tc.name)
+	}
+}
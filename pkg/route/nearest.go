@@ -0,0 +1,107 @@
+package route
+
+import (
+	"fmt"
+
+	"github.com/lunemec/ed-router/pkg/config"
+	"github.com/lunemec/ed-router/pkg/db"
+	"github.com/lunemec/ed-router/pkg/db/boltdb"
+	"github.com/lunemec/ed-router/pkg/distance"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Nearest lists systems nearest to the system given as argument, or all
+// the systems within --radius of it.
+func Nearest(cmd *cobra.Command, args []string) error {
+	return nearest(cmd, args[0], "", nil)
+}
+
+// NearestScoopable lists systems with scoopable star nearest to the system
+// given as argument.
+func NearestScoopable(cmd *cobra.Command, args []string) error {
+	return nearest(cmd, args[0], "scoopable ", db.Scoopable)
+}
+
+// NearestNeutron lists systems with neutron star nearest to the system
+// given as argument.
+func NearestNeutron(cmd *cobra.Command, args []string) error {
+	return nearest(cmd, args[0], "neutron ", db.Neutron)
+}
+
+// nearest prints systems selected by filter nearest to system query,
+// kind describes the selected systems.
+func nearest(cmd *cobra.Command, query, kind string, filter db.Filter) error {
+	count, err := cmd.Flags().GetInt("count")
+	if err != nil {
+		return err
+	}
+	if count <= 0 {
+		return errors.Errorf("count must be positive, got: %d", count)
+	}
+	radius, err := cmd.Flags().GetFloat64("radius")
+	if err != nil {
+		return err
+	}
+	if radius < 0 {
+		return errors.Errorf("radius must not be negative, got: %v", radius)
+	}
+
+	cfg, err := config.FromCommand(cmd)
+	if err != nil {
+		return err
+	}
+	store, err := boltdb.Open(cfg.IndexDBPath(), cfg.GalaxyDBPath(), true)
+	if err != nil {
+		return errors.Wrap(err, "unable to open database")
+	}
+	defer store.Close()
+
+	origin, err := db.Resolve(store, query)
+	if err != nil {
+		return err
+	}
+
+	var systems []db.System
+	if radius > 0 {
+		systems, err = store.WithinRadius(origin.Coordinates, radius, filter)
+	} else {
+		// The origin itself may be one of the nearest.
+		systems, err = store.Nearest(origin.Coordinates, count+1, filter)
+	}
+	if err != nil {
+		return err
+	}
+
+	var found []db.System
+	for _, s := range systems {
+		if s.ID64 != origin.ID64 {
+			found = append(found, s)
+		}
+	}
+	if radius > 0 {
+		fmt.Printf("%d %ssystems within %.1f LY of %s:\n", len(found), kind, radius, origin.Name)
+	} else {
+		if len(found) > count {
+			found = found[:count]
+		}
+		fmt.Printf("Nearest %ssystems to %s:\n", kind, origin.Name)
+	}
+
+	for i, s := range found {
+		full, err := store.SystemByID(s.ID64)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("[%d] %s (%.1f LY) SCOOPABLE: %s NEUTRON: %s\n", i+1, full.Name, distance.Distance(origin.Coordinates, s.Coordinates()), yesNo(s.IsScoopable), yesNo(s.IsNeutron))
+	}
+	return nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Y"
+	}
+	return "N"
+}
@@ -0,0 +1,84 @@
+package route
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lunemec/ed-router/pkg/pather"
+
+	"github.com/vbauerster/mpb/v5"
+	"github.com/vbauerster/mpb/v5/decor"
+)
+
+// search searches for path of p, rendering progress bar of how close to
+// the target the search got until it is done.
+func (r *router) search(p pather.Pather) ([]pather.Waypoint, float64, error) {
+	if r.anytime > 0 {
+		return r.searchAnytime(p)
+	}
+
+	var (
+		mu    sync.Mutex
+		event pather.Progress
+		total = int64(p.Distance())
+	)
+	pb := mpb.New(
+		mpb.WithRefreshRate(180 * time.Millisecond),
+	)
+	bar := pb.AddBar(total,
+		mpb.BarStyle("[=>-|"),
+		mpb.PrependDecorators(
+			decor.CountersNoUnit("% d / % d LY"),
+		),
+		mpb.AppendDecorators(
+			decor.Name(" ] "),
+			decor.Any(func(decor.Statistics) string {
+				mu.Lock()
+				defer mu.Unlock()
+				return fmt.Sprintf("%d nodes, %.0f LY corridor", event.Expanded, event.Corridor)
+			}),
+		))
+
+	var (
+		wg   sync.WaitGroup
+		done = make(chan struct{})
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case e := <-r.progress:
+				mu.Lock()
+				event = e
+				mu.Unlock()
+				bar.SetCurrent(int64(e.Distance - e.Closest))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	path, cost, err := p.PathContext(r.ctx)
+	close(done)
+	wg.Wait()
+
+	if err == nil {
+		bar.SetTotal(total, true)
+	} else {
+		bar.Abort(false)
+	}
+	pb.Wait()
+	return path, cost, err
+}
+
+// searchAnytime searches for path of p, printing every better path found
+// until r.anytime after the first one runs out.
+func (r *router) searchAnytime(p pather.Pather) ([]pather.Waypoint, float64, error) {
+	start := time.Now()
+	return p.PathAnytime(r.ctx, r.anytime, func(i pather.Improvement) {
+		fmt.Printf("Found route with cost %s, %d jumps (epsilon %.3g) after %s\n",
+			formatCost(r.optimize, i.Cost), len(i.Path)-1, i.Epsilon, time.Since(start).Round(time.Millisecond))
+	})
+}
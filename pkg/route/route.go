@@ -1,8 +1,13 @@
 package route
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/lunemec/ed-router/pkg/config"
 	"github.com/lunemec/ed-router/pkg/db/boltdb"
 	"github.com/lunemec/ed-router/pkg/distance"
 	"github.com/lunemec/ed-router/pkg/pather"
@@ -13,83 +18,508 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var (
-	IndexDB  = "index_xyz.db"
-	GalaxyDB = "galaxy.db"
-)
-
-// Route is the main entrypoint for path routing.Route
-// expects 2 arguments [from] and [to].
+// Route is the main entrypoint for path routing. Route
+// expects at least 2 arguments [from] and [to], any systems in between
+// (and --via) are visited in order.
 func Route(cmd *cobra.Command, args []string) error {
 	defer profile.Start().Stop()
 
-	db, err := boltdb.Open(IndexDB, GalaxyDB, true)
+	names, err := waypointNames(cmd, args)
 	if err != nil {
-		return errors.Wrap(err, "unable to open database")
+		return err
 	}
+	r, err := newRouter(cmd)
+	if err != nil {
+		return err
+	}
+	return r.route(names)
+}
 
-	fromName := args[0]
-	toName := args[1]
+// router plans routes with the ship and options given by command flags.
+type router struct {
+	db         *boltdb.DB
+	ship       ship.Ship
+	optimize   string
+	injections pather.Injections
+	opts       []pather.Option
+	// anytime is how long to keep improving the route, 0 is not at all.
+	anytime time.Duration
 
-	if fromName == toName {
-		return errors.New("what do you want from me?!")
+	// ctx cancels the searches, eg. on Ctrl-C.
+	ctx context.Context
+	// progress receives progress of the running search.
+	progress chan pather.Progress
+	// found are the legs already found, reused by the same searches.
+	found map[legKey]leg
+}
+
+func newRouter(cmd *cobra.Command) (*router, error) {
+	optimize, err := cmd.Flags().GetString("optimize")
+	if err != nil {
+		return nil, err
+	}
+	costFunc, ok := pather.CostFuncs[optimize]
+	if !ok {
+		return nil, errors.Errorf("unknown optimization: %s, use one of: %s", optimize, strings.Join(pather.CostFuncNames(), ", "))
 	}
 
-	ship := ship.New(32, 346.9, 1692.6, 5, 10.5, 878, ship.FSDRating["A"], ship.FSDClass[5])
-	fmt.Printf("Jump Range: %f \n", ship.JumpRange())
-	p, err := pather.New(db, ship, fromName, toName)
+	cfg, err := config.FromCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	db, err := boltdb.Open(cfg.IndexDBPath(), cfg.GalaxyDBPath(), true)
 	if err != nil {
-		return errors.Wrap(err, "unable to initialize new pather")
+		return nil, errors.Wrap(err, "unable to open database")
 	}
 
-	from := p.From()
-	to := p.To()
+	s, err := loadShip(cmd, cfg)
+	if err != nil {
+		return nil, err
+	}
+	s, err = loadCargoAndFuel(cmd, s)
+	if err != nil {
+		return nil, err
+	}
+	whiteDwarf, err := cmd.Flags().GetBool("white-dwarf")
+	if err != nil {
+		return nil, err
+	}
+	injectionCounts, err := cmd.Flags().GetStringToInt("injections")
+	if err != nil {
+		return nil, err
+	}
+	injections, err := pather.ParseInjections(injectionCounts)
+	if err != nil {
+		return nil, err
+	}
+	avoid, err := cmd.Flags().GetStringSlice("avoid")
+	if err != nil {
+		return nil, err
+	}
+	permits, err := cmd.Flags().GetStringSlice("permit")
+	if err != nil {
+		return nil, err
+	}
+	// Avoided systems are the same for all the legs.
+	avoided, err := pather.ResolveAvoid(db, append(cfg.Avoid, avoid...), append(cfg.Permits, permits...))
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := loadVolumes(cmd, cfg)
+	if err != nil {
+		return nil, err
+	}
+	epsilon, err := cmd.Flags().GetFloat64("epsilon")
+	if err != nil {
+		return nil, err
+	}
+	anytime, err := cmd.Flags().GetDuration("anytime")
+	if err != nil {
+		return nil, err
+	}
+	// Path found with epsilon 1 is already the cheapest one.
+	if anytime > 0 && epsilon <= 1 {
+		return nil, errors.New("--anytime needs --epsilon above 1")
+	}
+	budget, err := cmd.Flags().GetInt("budget")
+	if err != nil {
+		return nil, err
+	}
+	corridors, err := cmd.Flags().GetFloat64Slice("corridors")
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range corridors {
+		if c <= 0 {
+			return nil, errors.Errorf("corridor width must be positive, got: %v", c)
+		}
+	}
+
+	r := router{
+		db:         db,
+		ship:       s,
+		optimize:   optimize,
+		injections: injections,
+		anytime:    anytime,
+		ctx:        cmd.Context(),
+		progress:   make(chan pather.Progress),
+		found:      make(map[legKey]leg),
+	}
+	r.opts = append(r.opts,
+		pather.WithProgress(r.progress),
+		pather.WithCostFunc(costFunc),
+		pather.WithAvoid(avoided),
+		pather.WithVolumes(volumes...),
+		pather.WithCorridors(corridors...),
+		pather.WithBudget(budget),
+		pather.WithEpsilon(epsilon),
+	)
+	if whiteDwarf {
+		r.opts = append(r.opts, pather.WithWhiteDwarf())
+	}
+	return &r, nil
+}
+
+// pather creates new pather from system fromName to toName for ship s
+// with injections left and extra options.
+func (r *router) pather(s ship.Ship, fromName, toName string, injections pather.Injections, extra ...pather.Option) (pather.Pather, error) {
+	opts := append([]pather.Option{pather.WithInjections(injections)}, r.opts...)
+	opts = append(opts, extra...)
+	p, err := pather.New(r.db, s, fromName, toName, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to initialize new pather")
+	}
+	return p, nil
+}
+
+// leg is path found between two of the systems to visit.
+type leg struct {
+	p    pather.Pather
+	path []pather.Waypoint
+	cost float64
+}
+
+// legKey is what leg search depends on.
+type legKey struct {
+	from, to   string
+	ship       ship.Ship
+	injections pather.Injections
+	// refuel makes the ship scoop in the destination for the next leg.
+	refuel bool
+}
+
+// legPather creates pather searching the leg.
+func (r *router) legPather(k legKey) (pather.Pather, error) {
+	var extra []pather.Option
+	if k.refuel {
+		extra = append(extra, pather.WithDestinationRefuel())
+	}
+	return r.pather(k.ship, k.from, k.to, k.injections, extra...)
+}
+
+// route plans and prints route visiting systems names in order.
+func (r *router) route(names []string) error {
+	var (
+		s          = r.ship
+		injections = r.injections
+		legs       []leg
+	)
+	fmt.Printf("Jump Range: %f (%f with fuel remaining) \n", s.JumpRange(), s.JumpRangeWithRemainingFuel())
+
+	// Each leg continues with the ship state and injections left at the
+	// end of the previous one.
+	for i := 1; i < len(names); i++ {
+		fromName := names[i-1]
+		toName := names[i]
+
+		// Next leg starts where this one ends.
+		key := legKey{from: fromName, to: toName, ship: s, injections: injections, refuel: i < len(names)-1}
+		l, found := r.found[key]
+		if !found {
+			p, err := r.legPather(key)
+			if err != nil {
+				return err
+			}
+			l.p = p
+		}
+
+		from := l.p.From()
+		to := l.p.To()
 
-	fmt.Printf(`
+		fmt.Printf(`
+Leg %d: %s -> %s
 Start: %d at %+v
 End: %d at %+v
 Distance: %.1f LY
-`, from.ID64, from.Coordinates, to.ID64, to.Coordinates, p.Distance())
+`, i, fromName, toName, from.ID64, from.Coordinates, to.ID64, to.Coordinates, l.p.Distance())
+
+		if !found {
+			path, cost, err := r.search(l.p)
+			if errors.Cause(err) == pather.ErrNoPath {
+				r.printLegs(legs)
+				return r.notFound(l.p, s, fromName, toName, injections)
+			}
+			if err != nil {
+				return errors.Wrapf(err, "unable to find path from %s to %s", fromName, toName)
+			}
+			l.path, l.cost = path, cost
+		}
+		fmt.Printf(`
+Found path with cost: %s
+Corridor: %.0f LY
+Systems checked: %d
+`, formatCost(r.optimize, l.cost), l.p.Corridor(), l.p.Stats())
+
+		legs = append(legs, l)
+		last := l.path[len(l.path)-1]
+		s = last.Ship
+		for _, w := range l.path {
+			injections = injections.Use(w.Injection)
+		}
+	}
+
+	// Paths are printed once all the legs are found, because the ship may
+	// supercharge in the system where the next leg starts.
+	totalCost, totalJumps, totalDistance := r.printLegs(legs)
+	if len(names) > 2 {
+		fmt.Printf("\nTotal: %s, %d jumps, %.1f LY\n", formatCost(r.optimize, totalCost), totalJumps, totalDistance)
+	}
+	return nil
+}
+
+// printLegs prints paths of the legs with their subtotals and returns the
+// totals.
+func (r *router) printLegs(legs []leg) (float64, int, float64) {
+	var (
+		totalCost     float64
+		totalDistance float64
+		totalJumps    int
+	)
+	for i, l := range legs {
+		fmt.Printf("\nLeg %d:\n", i+1)
+
+		// Waypoint of the previous leg is not repeated.
+		var (
+			path   = l.path
+			prev   *pather.System
+			offset int
+		)
+		if i > 0 {
+			path = path[1:]
+			prev = l.p.From()
+			offset = totalJumps + 1
+		}
+		// The next leg continues with jump from the last system.
+		var next *pather.Waypoint
+		if i+1 < len(legs) && len(legs[i+1].path) > 1 {
+			next = &legs[i+1].path[1]
+		}
+		printPath(r.db, prev, path, offset, next)
+
+		legDistance := pathDistance(l.p.From(), path)
+		jumps := len(path)
+		if i == 0 {
+			jumps--
+		}
+		fmt.Printf("Leg %d subtotal: %s, %d jumps, %.1f LY\n", i+1, formatCost(r.optimize, l.cost), jumps, legDistance)
+
+		totalCost += l.cost
+		totalJumps += jumps
+		totalDistance += legDistance
+	}
+	return totalCost, totalJumps, totalDistance
+}
 
-	path, cost, found := p.Path()
-	if !found {
-		fmt.Println("No path found.")
+// notFound explains why no path from fromName to toName was found.
+func (r *router) notFound(p pather.Pather, s ship.Ship, fromName, toName string, injections pather.Injections) error {
+	// There is path ignoring fuel, so the ship runs out of it on the way.
+	if p.Reachable() {
+		return errors.Wrapf(ship.ErrNotEnoughFuel, "no path found from %s to %s, %d jumps rejected with %.2f T fuel reserve", fromName, toName, p.FuelRejected(), s.FuelReserve())
+	}
+	if injections == (pather.Injections{}) {
+		fmt.Printf("No path found from %s to %s within %.0f LY corridor, FSD injections may help, see --injections.\n", fromName, toName, p.Corridor())
 		return nil
 	}
-	fmt.Printf(`
-Found path with cost: %f
-Systems checked: %d
-`, cost, p.Stats())
+	fmt.Printf("No path found from %s to %s within %.0f LY corridor, wider --corridors may help.\n", fromName, toName, p.Corridor())
+	return nil
+}
+
+// waypointNames returns names of all the systems to visit in order,
+// [from] [via...] [to] from arguments and --via.
+func waypointNames(cmd *cobra.Command, args []string) ([]string, error) {
+	via, err := cmd.Flags().GetStringSlice("via")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	names = append(names, args[:len(args)-1]...)
+	names = append(names, via...)
+	names = append(names, args[len(args)-1])
+
+	for i := 1; i < len(names); i++ {
+		if names[i-1] == names[i] {
+			return nil, errors.New("what do you want from me?!")
+		}
+	}
+	return names, nil
+}
 
+// printPath prints the path systems numbered from offset, distance
+// of the first one is from prevSystem unless it is nil. Next is waypoint
+// the route continues to after the path, if any.
+func printPath(db *boltdb.DB, prevSystem *pather.System, path []pather.Waypoint, offset int, next *pather.Waypoint) {
 	var (
-		prevSystem      *pather.System
 		dist            float64
 		neutron, refuel string
+		lowFuel         string
+		injection       string
 	)
 	for i, system := range path {
 		if prevSystem != nil {
 			dist = distance.Distance(prevSystem.Coordinates, system.Coordinates)
 		}
 
-		if system.Neutron {
-			neutron = "Y"
-		} else {
+		// The ship supercharges here when the jump to the next system
+		// is supercharged.
+		following := next
+		if i+1 < len(path) {
+			following = &path[i+1]
+		}
+		switch {
+		case following == nil || !following.Supercharged:
 			neutron = "N"
+		case system.Neutron:
+			neutron = "Y"
+		default:
+			neutron = "WD"
 		}
 
-		if system.Scoopable {
+		if system.Refuel {
 			refuel = "Y"
 		} else {
 			refuel = "N"
 		}
 
+		lowFuel = ""
+		if system.Arrival.BelowReserve() {
+			lowFuel = "BELOW RESERVE"
+		}
+
+		injection = ""
+		if system.Injection != pather.NoInjection {
+			injection = fmt.Sprintf("INJECTION: %s ", system.Injection)
+		}
+
 		fullSystem, err := db.SystemByID(system.ID64)
 		if err != nil {
 			fmt.Printf("%+v \n", err)
 		}
 
-		fmt.Printf("[%d] SUPERCHARGE: %s REFUEL: %s %s (%.1f LY) \n", i, neutron, refuel, fullSystem.Name, dist)
-		prevSystem = system
+		fmt.Printf("[%d] SUPERCHARGE: %s REFUEL: %s %s%s (%.1f LY) FUEL: %.2f T %s\n", offset+i, neutron, refuel, injection, fullSystem.Name, dist, system.Arrival.FuelRemaining(), lowFuel)
+		prevSystem = system.System
 	}
-	return nil
+}
+
+// pathDistance returns distance in LY travelled along the path from start.
+func pathDistance(start *pather.System, path []pather.Waypoint) float64 {
+	var (
+		total float64
+		prev  = start
+	)
+	for _, w := range path {
+		total += distance.Distance(prev.Coordinates, w.Coordinates)
+		prev = w.System
+	}
+	return total
+}
+
+// loadShip reads ship from the --ship loadout file or --profile from the
+// config, or returns the default ship when neither is given.
+func loadShip(cmd *cobra.Command, cfg *config.Config) (ship.Ship, error) {
+	loadoutFile, err := cmd.Flags().GetString("ship")
+	if err != nil {
+		return nil, err
+	}
+	profile, err := cmd.Flags().GetString("profile")
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case loadoutFile != "" && profile != "":
+		return nil, errors.New("use either --ship or --profile, not both")
+	case profile != "":
+		l, err := cfg.Ship(profile)
+		if err != nil {
+			return nil, err
+		}
+		return l.Ship(), nil
+	case loadoutFile == "":
+		return ship.New(32, 346.9, 1692.6, 5, 10.5, 878, ship.FSDRating["A"], ship.FSDClass[5]), nil
+	}
+
+	f, err := os.Open(loadoutFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open ship loadout")
+	}
+	defer f.Close()
+
+	l, err := ship.FromLoadout(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to load ship from: %s", loadoutFile)
+	}
+	return l.Ship(), nil
+}
+
+// loadCargoAndFuel sets --cargo, --fuel and --reserve on the ship, the ship
+// has full tank and no cargo unless given.
+func loadCargoAndFuel(cmd *cobra.Command, s ship.Ship) (ship.Ship, error) {
+	cargo, err := cmd.Flags().GetFloat64("cargo")
+	if err != nil {
+		return nil, err
+	}
+	if cargo < 0 {
+		return nil, errors.New("cargo must not be negative")
+	}
+	s = s.WithCargo(cargo)
+
+	if cmd.Flags().Changed("fuel") {
+		fuel, err := cmd.Flags().GetFloat64("fuel")
+		if err != nil {
+			return nil, err
+		}
+		if fuel < 0 || fuel > s.FuelRemaining() {
+			return nil, errors.Errorf("fuel must be between 0 and fuel tank size %.2f T", s.FuelRemaining())
+		}
+		s = s.WithFuel(fuel)
+	}
+
+	reserve, err := cmd.Flags().GetFloat64("reserve")
+	if err != nil {
+		return nil, err
+	}
+	if reserve < 0 {
+		return nil, errors.New("fuel reserve must not be negative")
+	}
+	return s.WithFuelReserve(reserve), nil
+}
+
+// loadVolumes reads keep-out volumes from the --keep-out file, or the one
+// from the config.
+func loadVolumes(cmd *cobra.Command, cfg *config.Config) ([]pather.Volume, error) {
+	path, err := cmd.Flags().GetString("keep-out")
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		path = cfg.KeepOut
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open keep-out volumes")
+	}
+	defer f.Close()
+
+	volumes, err := pather.LoadVolumes(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to load keep-out volumes from: %s", path)
+	}
+	return volumes, nil
+}
+
+// formatCost formats route cost in units of the optimization.
+func formatCost(optimize string, cost float64) string {
+	switch optimize {
+	case "time":
+		return time.Duration(cost * float64(time.Second)).Round(time.Second).String()
+	case "fuel":
+		return fmt.Sprintf("%.2f T of fuel", cost)
+	case "jumps":
+		return fmt.Sprintf("%.0f jumps", cost)
+	}
+	return fmt.Sprintf("%.1f", cost)
 }
@@ -0,0 +1,151 @@
+package route
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/lunemec/ed-router/pkg/db"
+	"github.com/lunemec/ed-router/pkg/pather"
+	"github.com/lunemec/ed-router/pkg/tour"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Tour is the entrypoint for tour planning. Tour finds good order to visit
+// all the systems given as arguments, optionally starting in --start and
+// ending in --end, which returns to the start when they are the same, and
+// plans route through them.
+func Tour(cmd *cobra.Command, args []string) error {
+	start, err := cmd.Flags().GetString("start")
+	if err != nil {
+		return err
+	}
+	end, err := cmd.Flags().GetString("end")
+	if err != nil {
+		return err
+	}
+
+	r, err := newRouter(cmd)
+	if err != nil {
+		return err
+	}
+	names, ids, err := uniqueNames(r.db, append(append([]string{start}, args...), end))
+	if err != nil {
+		return err
+	}
+	if len(names) < 2 {
+		return errors.New("tour needs at least 2 systems to visit")
+	}
+	startIndex, endIndex := tour.None, tour.None
+	if start != "" {
+		startIndex, err = systemIndex(r.db, ids, start)
+		if err != nil {
+			return err
+		}
+	}
+	if end != "" {
+		endIndex, err = systemIndex(r.db, ids, end)
+		if err != nil {
+			return err
+		}
+	}
+
+	costs, err := r.costs(names)
+	if err != nil {
+		return err
+	}
+
+	order := tour.Order(costs, startIndex, endIndex)
+	var ordered []string
+	for i, o := range order {
+		ordered = append(ordered, names[o])
+		if i > 0 && math.IsInf(costs[order[i-1]][o], 1) {
+			return errors.Errorf("no tour found, there is no route from %s to %s", names[order[i-1]], names[o])
+		}
+	}
+	fmt.Printf("\nTour order: %s\nEstimated cost: %s\n", strings.Join(ordered, " -> "), formatCost(r.optimize, tour.Cost(costs, order)))
+
+	// Legs found computing the costs are reused.
+	return r.route(ordered)
+}
+
+// uniqueNames returns non-empty names of different systems, in order, and
+// ID64s of the systems.
+func uniqueNames(store db.SystemStore, names []string) ([]string, []uint64, error) {
+	var (
+		out  []string
+		ids  []uint64
+		seen = make(map[uint64]bool)
+	)
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		system, err := db.Resolve(store, name)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid system to visit")
+		}
+		if seen[system.ID64] {
+			continue
+		}
+		seen[system.ID64] = true
+		out = append(out, name)
+		ids = append(ids, system.ID64)
+	}
+	return out, ids, nil
+}
+
+// systemIndex returns index of system name in ids.
+func systemIndex(store db.SystemStore, ids []uint64, name string) (int, error) {
+	system, err := db.Resolve(store, name)
+	if err != nil {
+		return 0, err
+	}
+	for i, id := range ids {
+		if id == system.ID64 {
+			return i, nil
+		}
+	}
+	return 0, errors.Errorf("system not visited: %s", name)
+}
+
+// costs returns matrix of route costs between each pair of the systems,
+// math.Inf(1) when there is no route. The costs are computed with the
+// starting ship state in one direction only and taken as symmetric.
+func (r *router) costs(names []string) ([][]float64, error) {
+	costs := make([][]float64, len(names))
+	for i := range costs {
+		costs[i] = make([]float64, len(names))
+	}
+
+	var (
+		pairs = len(names) * (len(names) - 1) / 2
+		done  int
+	)
+	for i := range names {
+		for j := i + 1; j < len(names); j++ {
+			done++
+			fmt.Printf("Computing route costs (%d/%d): %s -> %s\n", done, pairs, names[i], names[j])
+
+			// Most of the legs continue with the next one.
+			key := legKey{from: names[i], to: names[j], ship: r.ship, injections: r.injections, refuel: true}
+			p, err := r.legPather(key)
+			if err != nil {
+				return nil, err
+			}
+			path, cost, err := r.search(p)
+			if errors.Cause(err) == pather.ErrNoPath {
+				cost = math.Inf(1)
+			} else if err != nil {
+				return nil, errors.Wrapf(err, "unable to find path from %s to %s", names[i], names[j])
+			} else {
+				r.found[key] = leg{p: p, path: path, cost: cost}
+			}
+			costs[i][j] = cost
+			costs[j][i] = cost
+		}
+	}
+	return costs, nil
+}
@@ -0,0 +1,276 @@
+package ship
+
+import (
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// Loadout is set of ship parameters relevant for routing.
+type Loadout struct {
+	FuelTank                float64 `yaml:"fuel_tank"` // main fuel tank size in tons
+	Mass                    float64 `yaml:"mass"`      // mass with full fuel tank and no cargo in tons
+	FSDClass                int     `yaml:"fsd_class"`
+	FSDRating               string  `yaml:"fsd_rating"`
+	FSDOptimalMass          float64 `yaml:"fsd_optimal_mass"`
+	MaxFuelPerJump          float64 `yaml:"max_fuel_per_jump"`
+	GuardianFSDBoosterRange float64 `yaml:"guardian_fsd_booster_range"`
+	ScoopRate               float64 `yaml:"scoop_rate"`          // max scoop rate in kg/s
+	Reservoir               float64 `yaml:"reservoir,omitempty"` // FSD-side fuel reservoir in tons
+}
+
+type fsdStats struct {
+	optimalMass    float64
+	maxFuelPerJump float64
+}
+
+var (
+	// fsd maps FSD class -> rating -> unengineered stats.
+	fsd = map[int]map[string]fsdStats{
+		2: {"E": {48, 0.6}, "D": {54, 0.6}, "C": {60, 0.6}, "B": {75, 0.8}, "A": {90, 0.9}},
+		3: {"E": {80, 1.2}, "D": {90, 1.2}, "C": {100, 1.2}, "B": {125, 1.5}, "A": {150, 1.8}},
+		4: {"E": {280, 2}, "D": {315, 2}, "C": {350, 2}, "B": {438, 2.5}, "A": {525, 3}},
+		5: {"E": {560, 3.3}, "D": {630, 3.3}, "C": {700, 3.3}, "B": {875, 4.1}, "A": {1050, 5}},
+		6: {"E": {960, 5.3}, "D": {1080, 5.3}, "C": {1200, 5.3}, "B": {1500, 6.6}, "A": {1800, 8}},
+		7: {"E": {1440, 8.5}, "D": {1620, 8.5}, "C": {1800, 8.5}, "B": {2250, 10.6}, "A": {2700, 12.8}},
+	}
+	// guardianFSDBoosterRange maps guardian FSD booster class -> range bonus in LY.
+	guardianFSDBoosterRange = map[int]float64{
+		1: 4,
+		2: 6,
+		3: 7.75,
+		4: 9.25,
+		5: 10.5,
+	}
+	// fuelScoopRate maps fuel scoop class -> rating -> scoop rate in kg/s.
+	fuelScoopRate = map[int]map[string]float64{
+		1: {"E": 18, "D": 24, "C": 30, "B": 36, "A": 42},
+		2: {"E": 32, "D": 43, "C": 54, "B": 65, "A": 75},
+		3: {"E": 75, "D": 100, "C": 126, "B": 151, "A": 176},
+		4: {"E": 147, "D": 196, "C": 245, "B": 294, "A": 342},
+		5: {"E": 247, "D": 330, "C": 412, "B": 494, "A": 577},
+		6: {"E": 376, "D": 502, "C": 627, "B": 752, "A": 878},
+		7: {"E": 534, "D": 712, "C": 890, "B": 1068, "A": 1245},
+	}
+	// journalRatings maps class number used in journal item names to rating.
+	journalRatings = map[string]string{
+		"1": "E",
+		"2": "D",
+		"3": "C",
+		"4": "B",
+		"5": "A",
+	}
+
+	journalFSD          = regexp.MustCompile(`^int_hyperdrive_size(\d)_class(\d)$`)
+	journalGuardian     = regexp.MustCompile(`^int_guardianfsdbooster_size(\d)$`)
+	journalFuelScoop    = regexp.MustCompile(`^int_fuelscoop_size(\d)_class(\d)$`)
+	errNoFSD            = errors.New("loadout has no frame shift drive")
+	errUnknownLoadout   = errors.New("unknown loadout format, expected journal Loadout event, EDSY or Coriolis export")
+	errMissingFuelStats = errors.New("loadout is missing unladen mass or fuel capacity")
+)
+
+// FromLoadout reads ship loadout from the journal Loadout event JSON
+// (which is also what EDSY exports) or Coriolis ship-loadout export.
+func FromLoadout(r io.Reader) (Loadout, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Loadout{}, errors.Wrap(err, "unable to read loadout")
+	}
+
+	var probe struct {
+		Modules    []interface{} `json:"Modules"`
+		Components interface{}   `json:"components"`
+	}
+	err = json.Unmarshal(b, &probe)
+	if err != nil {
+		return Loadout{}, errors.Wrap(err, "unable to decode loadout JSON")
+	}
+
+	var l Loadout
+	switch {
+	case probe.Modules != nil:
+		l, err = fromJournalLoadout(b)
+	case probe.Components != nil:
+		l, err = fromCoriolisLoadout(b)
+	default:
+		return Loadout{}, errUnknownLoadout
+	}
+	if err != nil {
+		return Loadout{}, err
+	}
+	return l, l.Validate()
+}
+
+// Validate checks that the loadout describes ship able to jump.
+func (l Loadout) Validate() error {
+	if _, ok := FSDClass[l.FSDClass]; !ok {
+		return errors.Errorf("unknown FSD class: %d", l.FSDClass)
+	}
+	if _, ok := FSDRating[l.FSDRating]; !ok {
+		return errors.Errorf("unknown FSD rating: %s", l.FSDRating)
+	}
+	if l.Mass <= 0 || l.FSDOptimalMass <= 0 || l.MaxFuelPerJump <= 0 {
+		return errors.New("mass, FSD optimal mass and max fuel per jump must be positive")
+	}
+	if l.FuelTank < l.MaxFuelPerJump {
+		return errors.New("fuel tank is smaller than max fuel per jump")
+	}
+	return nil
+}
+
+// Ship creates new ship with full fuel tank from the loadout.
+func (l Loadout) Ship() Ship {
+	return New(l.FuelTank, l.Mass, l.FSDOptimalMass, l.MaxFuelPerJump, l.GuardianFSDBoosterRange, l.ScoopRate, FSDRating[l.FSDRating], FSDClass[l.FSDClass]).
+		WithReservoir(l.Reservoir)
+}
+
+// SetFSD sets FSD class, rating and its unengineered stats.
+func (l *Loadout) SetFSD(class int, rating string) error {
+	stats, ok := fsd[class][rating]
+	if !ok {
+		return errors.Errorf("unknown FSD: %d%s", class, rating)
+	}
+	l.FSDClass = class
+	l.FSDRating = rating
+	l.FSDOptimalMass = stats.optimalMass
+	l.MaxFuelPerJump = stats.maxFuelPerJump
+	return nil
+}
+
+type journalLoadout struct {
+	UnladenMass  float64 `json:"UnladenMass"`
+	FuelCapacity struct {
+		Main    float64 `json:"Main"`
+		Reserve float64 `json:"Reserve"`
+	} `json:"FuelCapacity"`
+	Modules []struct {
+		Slot        string `json:"Slot"`
+		Item        string `json:"Item"`
+		Engineering struct {
+			Modifiers []struct {
+				Label string  `json:"Label"`
+				Value float64 `json:"Value"`
+			} `json:"Modifiers"`
+		} `json:"Engineering"`
+	} `json:"Modules"`
+}
+
+func fromJournalLoadout(b []byte) (Loadout, error) {
+	var (
+		jl     journalLoadout
+		l      Loadout
+		hasFSD bool
+	)
+	err := json.Unmarshal(b, &jl)
+	if err != nil {
+		return l, errors.Wrap(err, "unable to decode journal loadout")
+	}
+	if jl.UnladenMass <= 0 || jl.FuelCapacity.Main <= 0 {
+		return l, errMissingFuelStats
+	}
+	l.FuelTank = jl.FuelCapacity.Main
+	l.Mass = jl.UnladenMass + jl.FuelCapacity.Main
+	l.Reservoir = jl.FuelCapacity.Reserve
+
+	for _, module := range jl.Modules {
+		item := strings.ToLower(module.Item)
+
+		if m := journalFSD.FindStringSubmatch(item); m != nil {
+			class, _ := strconv.Atoi(m[1])
+			err = l.SetFSD(class, journalRatings[m[2]])
+			if err != nil {
+				return l, err
+			}
+			for _, modifier := range module.Engineering.Modifiers {
+				switch modifier.Label {
+				case "FSDOptimalMass":
+					l.FSDOptimalMass = modifier.Value
+				case "MaxFuelPerJump":
+					l.MaxFuelPerJump = modifier.Value
+				}
+			}
+			hasFSD = true
+		}
+		if m := journalGuardian.FindStringSubmatch(item); m != nil {
+			class, _ := strconv.Atoi(m[1])
+			l.GuardianFSDBoosterRange = guardianFSDBoosterRange[class]
+		}
+		if m := journalFuelScoop.FindStringSubmatch(item); m != nil {
+			class, _ := strconv.Atoi(m[1])
+			l.ScoopRate = fuelScoopRate[class][journalRatings[m[2]]]
+		}
+	}
+	if !hasFSD {
+		return l, errNoFSD
+	}
+	return l, nil
+}
+
+type coriolisModule struct {
+	Class  int    `json:"class"`
+	Rating string `json:"rating"`
+	Group  string `json:"group"`
+	// Modifications are stored as 1/100 of percent, 10000 is +100%.
+	Modifications map[string]float64 `json:"modifications"`
+}
+
+type coriolisLoadout struct {
+	Components struct {
+		Standard struct {
+			FrameShiftDrive *coriolisModule `json:"frameShiftDrive"`
+		} `json:"standard"`
+		Internal []*coriolisModule `json:"internal"`
+	} `json:"components"`
+	Stats struct {
+		UnladenMass  float64 `json:"unladenMass"`
+		FuelCapacity float64 `json:"fuelCapacity"`
+	} `json:"stats"`
+}
+
+func fromCoriolisLoadout(b []byte) (Loadout, error) {
+	var (
+		cl coriolisLoadout
+		l  Loadout
+	)
+	err := json.Unmarshal(b, &cl)
+	if err != nil {
+		return l, errors.Wrap(err, "unable to decode coriolis loadout")
+	}
+	if cl.Stats.UnladenMass <= 0 || cl.Stats.FuelCapacity <= 0 {
+		return l, errMissingFuelStats
+	}
+	l.FuelTank = cl.Stats.FuelCapacity
+	l.Mass = cl.Stats.UnladenMass + cl.Stats.FuelCapacity
+
+	fsdModule := cl.Components.Standard.FrameShiftDrive
+	if fsdModule == nil {
+		return l, errNoFSD
+	}
+	err = l.SetFSD(fsdModule.Class, fsdModule.Rating)
+	if err != nil {
+		return l, err
+	}
+	l.FSDOptimalMass *= 1 + fsdModule.Modifications["optmass"]/10000
+	l.MaxFuelPerJump *= 1 + fsdModule.Modifications["maxfuel"]/10000
+
+	for _, module := range cl.Components.Internal {
+		// Empty slots are null.
+		if module == nil {
+			continue
+		}
+		switch module.Group {
+		case "Guardian Frame Shift Drive Booster":
+			l.GuardianFSDBoosterRange = guardianFSDBoosterRange[module.Class]
+		case "Fuel Scoop":
+			l.ScoopRate = fuelScoopRate[module.Class][module.Rating]
+		}
+	}
+	return l, nil
+}
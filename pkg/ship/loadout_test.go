@@ -0,0 +1,88 @@
+package ship
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testJournalLoadout = `{
+	"timestamp": "2020-10-01T12:00:00Z",
+	"event": "Loadout",
+	"Ship": "anaconda",
+	"UnladenMass": 314.9,
+	"CargoCapacity": 0,
+	"FuelCapacity": {"Main": 32.0, "Reserve": 1.07},
+	"Modules": [
+		{"Slot": "FrameShiftDrive", "Item": "Int_Hyperdrive_Size5_Class5",
+		 "Engineering": {"Modifiers": [
+			{"Label": "FSDOptimalMass", "Value": 1692.6, "OriginalValue": 1050}
+		 ]}},
+		{"Slot": "Slot01_Size6", "Item": "int_fuelscoop_size6_class5"},
+		{"Slot": "Slot02_Size5", "Item": "int_guardianfsdbooster_size5"}
+	]
+}`
+
+const testCoriolisLoadout = `{
+	"$schema": "https://coriolis.io/schemas/ship-loadout/4.json#",
+	"name": "Explorer",
+	"ship": "Anaconda",
+	"components": {
+		"standard": {
+			"frameShiftDrive": {"class": 5, "rating": "A", "enabled": true, "priority": 1,
+				"modifications": {"optmass": 6120}}
+		},
+		"internal": [
+			{"class": 6, "rating": "A", "enabled": true, "priority": 1, "group": "Fuel Scoop"},
+			null,
+			{"class": 5, "rating": "H", "enabled": true, "priority": 1, "group": "Guardian Frame Shift Drive Booster"}
+		]
+	},
+	"stats": {"unladenMass": 314.9, "fuelCapacity": 32}
+}`
+
+func TestFromJournalLoadout(t *testing.T) {
+	l, err := FromLoadout(strings.NewReader(testJournalLoadout))
+	assert.NoError(t, err)
+	assert.Equal(t, Loadout{
+		FuelTank:                32,
+		Mass:                    346.9,
+		FSDClass:                5,
+		FSDRating:               "A",
+		FSDOptimalMass:          1692.6,
+		MaxFuelPerJump:          5,
+		GuardianFSDBoosterRange: 10.5,
+		ScoopRate:               878,
+		Reservoir:               1.07,
+	}, l)
+	assert.EqualValues(t, 67.56, math.Round(l.Ship().JumpRange()*100.0)/100.0)
+}
+
+func TestFromCoriolisLoadout(t *testing.T) {
+	l, err := FromLoadout(strings.NewReader(testCoriolisLoadout))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, l.FSDClass)
+	assert.Equal(t, "A", l.FSDRating)
+	assert.InDelta(t, 1692.6, l.FSDOptimalMass, 0.01)
+	assert.EqualValues(t, 5, l.MaxFuelPerJump)
+	assert.EqualValues(t, 10.5, l.GuardianFSDBoosterRange)
+	assert.EqualValues(t, 878, l.ScoopRate)
+	assert.EqualValues(t, 346.9, l.Mass)
+	assert.EqualValues(t, 32, l.FuelTank)
+}
+
+func TestFromLoadoutErrors(t *testing.T) {
+	_, err := FromLoadout(strings.NewReader(`{"foo": "bar"}`))
+	assert.Equal(t, errUnknownLoadout, err)
+
+	_, err = FromLoadout(strings.NewReader(`{"UnladenMass": 300, "FuelCapacity": {"Main": 32}, "Modules": []}`))
+	assert.Equal(t, errNoFSD, err)
+
+	_, err = FromLoadout(strings.NewReader(`{"Modules": []}`))
+	assert.Equal(t, errMissingFuelStats, err)
+
+	_, err = FromLoadout(strings.NewReader(`not json`))
+	assert.Error(t, err)
+}
@@ -9,7 +9,16 @@ type Ship interface {
 	Jump(distance float64) (Ship, error)
 	JumpRange() float64
 	JumpRangeWithRemainingFuel() float64
+	MaxJumpRange() float64
 	SecondsToScoop() float64
+	FuelRemaining() float64
+	Refuel() Ship
+	WithFuel(fuel float64) Ship
+	WithCargo(cargo float64) Ship
+	WithReservoir(reservoir float64) Ship
+	WithFuelReserve(reserve float64) Ship
+	FuelReserve() float64
+	BelowReserve() bool
 }
 
 type ship struct {
@@ -18,8 +27,11 @@ type ship struct {
 	fuelTank  float64 // how big the fuel tank is in tons
 
 	fuelRemaining           float64
+	reservoir               float64 // FSD-side fuel reservoir in tons, does not feed the FSD
+	fuelReserve             float64 // minimum fuel in tons to keep in the main tank
 	scoopRate               float64 // max scoopRate in kg/s (module description)
-	mass                    float64
+	mass                    float64 // mass with full fuel tank and no cargo
+	cargo                   float64 // cargo carried in tons
 	currentMass             float64
 	fsdOptimalMass          float64
 	guardianFSDBoosterRange float64
@@ -77,7 +89,6 @@ func New(fuelTank, mass, fsdOptimalMass, maxFuelPerJump, guardianFSDBoosterRange
 // with calculated fuelRemaining.
 // https://elite-dangerous.fandom.com/wiki/Frame_Shift_Drive#Hyperspace_Fuel_Equation
 func (s ship) Jump(distance float64) (Ship, error) {
-	return s, nil
 	fuelRequired := s.fuelToJump(distance)
 	if s.fuelRemaining < fuelRequired {
 		return s, ErrNotEnoughFuel
@@ -88,22 +99,110 @@ func (s ship) Jump(distance float64) (Ship, error) {
 }
 
 func (s ship) fuelToJump(distance float64) float64 {
-	distance = distance - s.guardianFSDBoosterRange
+	// Guardian booster extends every jump in proportion, the FSD jumps
+	// only the rest of it.
+	distance -= s.guardianFSDBoosterRange * (distance / s.JumpRange())
 	return s.linearConstant * 0.001 * math.Pow((distance*s.currentMass)/s.fsdOptimalMass, s.powerConstant)
 }
 
-// JumpRange calculates current jump range based on fuel remaining.
-// Does not take into account cargo capacity, assuming 0T of cargo.
+// JumpRange calculates current max jump range based on current mass,
+// which includes fuel remaining and cargo.
 func (s ship) JumpRange() float64 {
-	return ((s.fsdOptimalMass / s.currentMass) * math.Pow((1000*s.maxFuelPerJump)/s.linearConstant, 1/s.powerConstant)) + s.guardianFSDBoosterRange
+	return s.rangeWithFuel(s.maxFuelPerJump)
 }
 
+// JumpRangeWithRemainingFuel is JumpRange limited by how much fuel is left,
+// when there is less fuel than the FSD can use for a single jump.
 func (s ship) JumpRangeWithRemainingFuel() float64 {
-	return 0
+	return s.rangeWithFuel(math.Min(s.fuelRemaining, s.maxFuelPerJump))
+}
+
+// MaxJumpRange is the best case jump range with the cargo aboard, when
+// there is only fuel for the jump left in the tank. No jump of the ship
+// can be longer, whatever the fuel remaining.
+func (s ship) MaxJumpRange() float64 {
+	fuel := math.Min(s.maxFuelPerJump, s.fuelTank)
+	s.currentMass += fuel - s.fuelRemaining
+	return s.rangeWithFuel(fuel)
+}
+
+// rangeWithFuel calculates jump range when using fuel in tons. Guardian
+// booster adds its range to the jump with max fuel per jump, shorter jumps
+// are extended in the same proportion.
+func (s ship) rangeWithFuel(fuel float64) float64 {
+	// Guardian booster does not jump without fuel for the FSD.
+	if fuel <= 0 {
+		return 0
+	}
+	base := s.fsdRangeWithFuel(fuel)
+	return base + s.guardianFSDBoosterRange*(base/s.fsdRangeWithFuel(s.maxFuelPerJump))
+}
+
+// fsdRangeWithFuel calculates jump range of the FSD without guardian
+// booster when using fuel in tons.
+func (s ship) fsdRangeWithFuel(fuel float64) float64 {
+	return (s.fsdOptimalMass / s.currentMass) * math.Pow((1000*fuel)/s.linearConstant, 1/s.powerConstant)
+}
+
+// WithFuel returns new copy of ship struct with fuel in tons remaining,
+// limited to the fuel tank size.
+func (s ship) WithFuel(fuel float64) Ship {
+	fuel = math.Max(0, math.Min(fuel, s.fuelTank))
+	s.currentMass += fuel - s.fuelRemaining
+	s.fuelRemaining = fuel
+	return s
+}
+
+// WithCargo returns new copy of ship struct carrying cargo in tons.
+func (s ship) WithCargo(cargo float64) Ship {
+	cargo = math.Max(0, cargo)
+	s.currentMass += cargo - s.cargo
+	s.cargo = cargo
+	return s
+}
+
+// WithReservoir returns new copy of ship struct with the fuel reservoir
+// of given size in tons. The reservoir keeps ship systems running and
+// can not be used for jumping, but adds to the ship mass.
+func (s ship) WithReservoir(reservoir float64) Ship {
+	reservoir = math.Max(0, reservoir)
+	s.mass += reservoir - s.reservoir
+	s.currentMass += reservoir - s.reservoir
+	s.reservoir = reservoir
+	return s
+}
+
+// WithFuelReserve returns new copy of ship struct which should always keep
+// at least reserve tons of fuel in the main tank.
+func (s ship) WithFuelReserve(reserve float64) Ship {
+	s.fuelReserve = math.Max(0, reserve)
+	return s
+}
+
+// FuelReserve returns minimum fuel in tons the ship should keep.
+func (s ship) FuelReserve() float64 {
+	return s.fuelReserve
+}
+
+// BelowReserve is true when there is less fuel remaining than the reserve.
+func (s ship) BelowReserve() bool {
+	return s.fuelRemaining < s.fuelReserve
+}
+
+// FuelRemaining returns how much fuel in tons is left in the tank.
+func (s ship) FuelRemaining() float64 {
+	return s.fuelRemaining
+}
+
+// Refuel returns new copy of ship struct with full fuel tank.
+func (s ship) Refuel() Ship {
+	s.currentMass += s.fuelTank - s.fuelRemaining
+	s.fuelRemaining = s.fuelTank
+	return s
 }
 
 // SecondsToScoop calculates how long it will take to
 // completely refuel the ship given the tank size, remaining fuel and scoop rate.
 func (s ship) SecondsToScoop() float64 {
-	return ((s.fuelTank - s.fuelRemaining) * 100) / s.scoopRate
+	return ((s.fuelTank - s.fuelRemaining) * 1000) / s.scoopRate
 }
@@ -71,3 +71,119 @@ func TestJumpOutOfFuel(t *testing.T) {
 	assert.Equal(t, 316.9, sh.currentMass)
 	assert.Equal(t, 73.15181131851537, s.JumpRange())
 }
+
+func TestJumpShorterThanGuardianBooster(t *testing.T) {
+	s := New(32, 346.9, 1692.6, 5, 10.5, 878, FSDRating["A"], FSDClass[5])
+
+	// Booster extends the range, short jumps are not free.
+	s2, err := s.Jump(5)
+	assert.NoError(t, err)
+	assert.True(t, s2.FuelRemaining() < s.FuelRemaining())
+	assert.True(t, s.(ship).fuelToJump(5) < s.(ship).fuelToJump(10.5))
+	assert.True(t, s.(ship).fuelToJump(10.5) > 0)
+}
+
+func TestRefuel(t *testing.T) {
+	s := New(32, 346.9, 1692.6, 5, 10.5, 878, FSDRating["A"], FSDClass[5])
+
+	s2, err := s.Jump(s.JumpRange())
+	assert.NoError(t, err)
+	assert.True(t, s2.FuelRemaining() < s.FuelRemaining())
+
+	s3 := s2.Refuel()
+	assert.Equal(t, s.FuelRemaining(), s3.FuelRemaining())
+	assert.InDelta(t, s.(ship).currentMass, s3.(ship).currentMass, 1e-9)
+	assert.InDelta(t, s.JumpRange(), s3.JumpRange(), 1e-9)
+}
+
+func TestSecondsToScoop(t *testing.T) {
+	s := New(32, 346.9, 1692.6, 5, 10.5, 878, FSDRating["A"], FSDClass[5])
+	assert.EqualValues(t, 0, s.SecondsToScoop())
+
+	s, err := s.Jump(s.JumpRange())
+	assert.NoError(t, err)
+	// 5T at 878 kg/s.
+	assert.InDelta(t, 5.69, s.SecondsToScoop(), 0.01)
+}
+
+func TestJumpRangeWithCargo(t *testing.T) {
+	s := New(32, 346.9, 1692.6, 5, 10.5, 878, FSDRating["A"], FSDClass[5])
+
+	laden := s.WithCargo(200)
+	assert.EqualValues(t, 546.9, laden.(ship).currentMass)
+	assert.True(t, laden.JumpRange() < s.JumpRange())
+	assert.InDelta(t, s.JumpRange(), laden.WithCargo(0).JumpRange(), 1e-9)
+
+	// Cargo stays aboard when refueling.
+	jumped, err := laden.Jump(laden.JumpRange())
+	assert.NoError(t, err)
+	assert.EqualValues(t, 200, jumped.Refuel().(ship).cargo)
+	assert.InDelta(t, laden.JumpRange(), jumped.Refuel().JumpRange(), 1e-9)
+}
+
+func TestWithFuel(t *testing.T) {
+	s := New(32, 346.9, 1692.6, 5, 10.5, 878, FSDRating["A"], FSDClass[5])
+
+	s2 := s.WithFuel(12)
+	assert.EqualValues(t, 12, s2.FuelRemaining())
+	assert.EqualValues(t, 326.9, s2.(ship).currentMass)
+	assert.True(t, s2.JumpRange() > s.JumpRange())
+
+	assert.EqualValues(t, 32, s.WithFuel(100).FuelRemaining())
+	assert.EqualValues(t, 0, s.WithFuel(-1).FuelRemaining())
+}
+
+func TestJumpRangeWithRemainingFuel(t *testing.T) {
+	s := New(32, 346.9, 1692.6, 5, 10.5, 878, FSDRating["A"], FSDClass[5])
+
+	// Enough fuel for the full jump.
+	assert.EqualValues(t, s.JumpRange(), s.JumpRangeWithRemainingFuel())
+
+	s2 := s.WithFuel(2)
+	assert.True(t, s2.JumpRangeWithRemainingFuel() < s2.JumpRange())
+
+	// The range is exactly what the fuel left allows.
+	s3, err := s2.Jump(s2.JumpRangeWithRemainingFuel())
+	assert.NoError(t, err)
+	assert.InDelta(t, 0, s3.FuelRemaining(), 1e-9)
+
+	assert.EqualValues(t, 0, s.WithFuel(0).JumpRangeWithRemainingFuel())
+}
+
+func TestWithReservoir(t *testing.T) {
+	s := New(32, 346.9, 1692.6, 5, 10.5, 878, FSDRating["A"], FSDClass[5])
+
+	s2 := s.WithReservoir(1.07)
+	assert.InDelta(t, 347.97, s2.(ship).currentMass, 1e-9)
+	assert.True(t, s2.JumpRange() < s.JumpRange())
+	// Reservoir is not part of the main tank.
+	assert.EqualValues(t, 32, s2.FuelRemaining())
+	assert.EqualValues(t, 0, s2.SecondsToScoop())
+}
+
+func TestBelowReserve(t *testing.T) {
+	s := New(32, 346.9, 1692.6, 5, 10.5, 878, FSDRating["A"], FSDClass[5]).WithFuelReserve(4)
+	assert.EqualValues(t, 4, s.FuelReserve())
+	assert.False(t, s.BelowReserve())
+
+	s2, err := s.WithFuel(8).Jump(s.JumpRange())
+	assert.NoError(t, err)
+	assert.True(t, s2.BelowReserve())
+	assert.False(t, s2.Refuel().BelowReserve())
+}
+
+func TestMaxJumpRange(t *testing.T) {
+	s := New(32, 346.9, 1692.6, 5, 10.5, 878, FSDRating["A"], FSDClass[5])
+	maxRange := s.MaxJumpRange()
+	assert.True(t, maxRange > s.JumpRange())
+
+	// Burning fuel never makes the range longer than the best case.
+	for s.FuelRemaining() >= 5 {
+		var err error
+		s, err = s.Jump(s.JumpRange())
+		assert.NoError(t, err)
+		assert.True(t, s.JumpRangeWithRemainingFuel() <= maxRange)
+	}
+	assert.InDelta(t, maxRange, s.WithFuel(5).JumpRange(), 1e-9)
+	assert.InDelta(t, maxRange, s.MaxJumpRange(), 1e-9)
+}
@@ -0,0 +1,138 @@
+// Package tour finds a good order to visit a set of places, given the cost
+// of travel between each pair of them (travelling salesman problem).
+// Finding the best order is NP-hard, so this uses nearest neighbour
+// heuristic improved by 2-opt, which is good enough for tens of places.
+package tour
+
+import (
+	"math"
+)
+
+// None means the first or last place is not fixed.
+const None = -1
+
+// Order returns order in which to visit all the places, where costs[i][j]
+// is cost of travel from place i to place j. The order is an open path,
+// it does not return to the first place. When start or end is not None,
+// the path begins or ends with that place, when they are the same place
+// the path returns to it.
+func Order(costs [][]float64, start, end int) []int {
+	if len(costs) == 0 {
+		return nil
+	}
+
+	var starts []int
+	if start != None {
+		starts = []int{start}
+	} else {
+		// Without fixed start, try nearest neighbour from every place.
+		for i := range costs {
+			if i != end || len(costs) == 1 {
+				starts = append(starts, i)
+			}
+		}
+	}
+
+	var (
+		best     []int
+		bestCost = math.Inf(1)
+	)
+	for _, s := range starts {
+		order := twoOpt(costs, nearestNeighbour(costs, s, end), start != None, end != None)
+		c := Cost(costs, order)
+		if best == nil || c < bestCost {
+			best = order
+			bestCost = c
+		}
+	}
+	return best
+}
+
+// Cost returns total cost of visiting places in order.
+func Cost(costs [][]float64, order []int) float64 {
+	var total float64
+	for i := 1; i < len(order); i++ {
+		total += costs[order[i-1]][order[i]]
+	}
+	return total
+}
+
+// nearestNeighbour builds order starting at start, always going to the
+// cheapest place not yet visited, end is kept for the last even when it is
+// the start.
+func nearestNeighbour(costs [][]float64, start, end int) []int {
+	var (
+		visited = make([]bool, len(costs))
+		order   = []int{start}
+		current = start
+	)
+	visited[start] = true
+	if end != None {
+		visited[end] = true
+	}
+
+	for len(order) < len(costs) {
+		next := None
+		for i := range costs {
+			if visited[i] {
+				continue
+			}
+			if next == None || costs[current][i] < costs[current][next] {
+				next = i
+			}
+		}
+		if next == None {
+			break
+		}
+		visited[next] = true
+		order = append(order, next)
+		current = next
+	}
+	if end != None {
+		order = append(order, end)
+	}
+	return order
+}
+
+// twoOpt improves the order by reversing its parts while it helps.
+// Fixed first and last places are never moved.
+func twoOpt(costs [][]float64, order []int, fixedStart, fixedEnd bool) []int {
+	var (
+		first = 0
+		last  = len(order) - 1
+	)
+	if fixedStart {
+		first++
+	}
+	if fixedEnd {
+		last--
+	}
+
+	bestCost := Cost(costs, order)
+	improved := true
+	for improved {
+		improved = false
+		for i := first; i < last; i++ {
+			for j := i + 1; j <= last; j++ {
+				reverse(order, i, j)
+				// Travel costs may not be symmetric, so the whole
+				// path cost is compared.
+				c := Cost(costs, order)
+				if c < bestCost {
+					bestCost = c
+					improved = true
+					continue
+				}
+				reverse(order, i, j)
+			}
+		}
+	}
+	return order
+}
+
+// reverse reverses order[i:j+1] in place.
+func reverse(order []int, i, j int) {
+	for ; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+}
@@ -0,0 +1,72 @@
+package tour
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lineCosts returns costs of places on a line at given positions.
+func lineCosts(positions ...float64) [][]float64 {
+	costs := make([][]float64, len(positions))
+	for i := range positions {
+		costs[i] = make([]float64, len(positions))
+		for j := range positions {
+			costs[i][j] = math.Abs(positions[i] - positions[j])
+		}
+	}
+	return costs
+}
+
+func TestOrderLine(t *testing.T) {
+	costs := lineCosts(0, 30, 10, 40, 20)
+
+	order := Order(costs, None, None)
+	assert.EqualValues(t, 40, Cost(costs, order))
+	assert.Len(t, order, 5)
+
+	order = Order(costs, 0, None)
+	assert.Equal(t, []int{0, 2, 4, 1, 3}, order)
+}
+
+func TestOrderFixedStartEnd(t *testing.T) {
+	costs := lineCosts(0, 30, 10, 40, 20)
+
+	// Starting in the middle, going to one end first is the best.
+	order := Order(costs, 4, None)
+	assert.Equal(t, 4, order[0])
+	assert.EqualValues(t, 60, Cost(costs, order))
+
+	order = Order(costs, 2, 4)
+	assert.Equal(t, 2, order[0])
+	assert.Equal(t, 4, order[4])
+	assert.EqualValues(t, 70, Cost(costs, order))
+
+	order = Order(costs, None, 0)
+	assert.Equal(t, []int{3, 1, 4, 2, 0}, order)
+
+	// Closed tour returns to the start.
+	order = Order(costs, 4, 4)
+	assert.Equal(t, []int{4, 1, 3, 2, 0, 4}, order)
+	assert.EqualValues(t, 80, Cost(costs, order))
+}
+
+func TestTwoOptImprovesNearestNeighbour(t *testing.T) {
+	// Nearest neighbour zig-zags around the start.
+	costs := lineCosts(0, -1, 1.5, -4)
+
+	nn := nearestNeighbour(costs, 0, None)
+	assert.Equal(t, []int{0, 1, 2, 3}, nn)
+	assert.EqualValues(t, 9, Cost(costs, nn))
+
+	order := twoOpt(costs, nn, true, false)
+	assert.Equal(t, []int{0, 2, 1, 3}, order)
+	assert.EqualValues(t, 7, Cost(costs, order))
+}
+
+func TestOrderSmall(t *testing.T) {
+	assert.Nil(t, Order(nil, None, None))
+	assert.Equal(t, []int{0}, Order([][]float64{{0}}, None, None))
+	assert.Equal(t, []int{1, 0}, Order(lineCosts(0, 10), 1, 0))
+}